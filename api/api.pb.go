@@ -25,6 +25,17 @@ It has these top-level messages:
 	AppendResult
 	Empty
 	ServerStatusResult
+	SubscribeRequest
+	GetTransactionRequest
+	GetTransactionResult
+	Receipt
+	ExpectedIndex
+	GetInclusionProofRequest
+	GetInclusionProofResult
+	GetConsistencyProofRequest
+	GetConsistencyProofResult
+	Envelope
+	SeedReset
 */
 package api
 
@@ -57,6 +68,11 @@ type ReadRequest struct {
 	Index int64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
 	// Count is the maximum number of transactions to read (if available).
 	Count int64 `protobuf:"varint,3,opt,name=count" json:"count,omitempty"`
+	// Types, if set, restricts the result to transactions matching one of
+	// these types. A type ending in "*" matches any type with the preceding
+	// prefix (eg. "symbiont/*"). Other transactions are skipped, and the
+	// ledger may scan past Count transactions to find matching ones.
+	Types []string `protobuf:"bytes,4,rep,name=types" json:"types,omitempty"`
 }
 
 func (m *ReadRequest) Reset()                    { *m = ReadRequest{} }
@@ -72,6 +88,11 @@ type ReadResult struct {
 	NetworkSeed []byte `protobuf:"bytes,1,opt,name=network_seed,json=networkSeed,proto3" json:"network_seed,omitempty"`
 	// Transactions are sequenced transactions read from the ledger.
 	Transactions []*SequencedTransaction `protobuf:"bytes,2,rep,name=transactions" json:"transactions,omitempty"`
+	// LastIndex is the index of the last transaction scanned by the read.
+	// When filtering on types this may be past the last transaction returned,
+	// and the next read should continue from LastIndex+1. If not set, it's
+	// the index of the last transaction returned.
+	LastIndex int64 `protobuf:"varint,3,opt,name=last_index,json=lastIndex" json:"last_index,omitempty"`
 }
 
 func (m *ReadResult) Reset()                    { *m = ReadResult{} }
@@ -128,6 +149,11 @@ type AppendRequest struct {
 	// Transactions are transactions to be appended to the ledger in an
 	// unspecified order.
 	Transactions []*UnsequencedTransaction `protobuf:"bytes,2,rep,name=transactions" json:"transactions,omitempty"`
+	// ExpectedLastIndex, if set, makes the append conditional on the last
+	// index of the ledger. The request will be rejected without appending
+	// anything if the ledger's last index doesn't match, allowing clients to
+	// detect concurrent writers. An index of 0 expects an empty ledger.
+	ExpectedLastIndex *ExpectedIndex `protobuf:"bytes,3,opt,name=expected_last_index,json=expectedLastIndex" json:"expected_last_index,omitempty"`
 }
 
 func (m *AppendRequest) Reset()                    { *m = AppendRequest{} }
@@ -142,6 +168,13 @@ func (m *AppendRequest) GetTransactions() []*UnsequencedTransaction {
 	return nil
 }
 
+func (m *AppendRequest) GetExpectedLastIndex() *ExpectedIndex {
+	if m != nil {
+		return m.ExpectedLastIndex
+	}
+	return nil
+}
+
 // UnsequencedTransaction is a transaction that hasn't been assigned an index
 // on the ledger yet. No assumptions should be made about when or whether it
 // will be appended to the ledger.
@@ -175,6 +208,9 @@ type AppendResult struct {
 	// provided transactions. Any subsequent appends are guaranteed to be order
 	// after this index.
 	LastIndex int64 `protobuf:"varint,2,opt,name=last_index,json=lastIndex" json:"last_index,omitempty"`
+	// Receipts, if set by the ledger, hold the outcome of each of the provided
+	// transactions, in the order they were provided in the request.
+	Receipts []*Receipt `protobuf:"bytes,3,rep,name=receipts" json:"receipts,omitempty"`
 }
 
 func (m *AppendResult) Reset()                    { *m = AppendResult{} }
@@ -182,6 +218,13 @@ func (m *AppendResult) String() string            { return proto.CompactTextStri
 func (*AppendResult) ProtoMessage()               {}
 func (*AppendResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
+func (m *AppendResult) GetReceipts() []*Receipt {
+	if m != nil {
+		return m.Receipts
+	}
+	return nil
+}
+
 // Empty as an empty message.
 type Empty struct {
 }
@@ -212,6 +255,22 @@ type ServerStatusResult struct {
 	// process of catching up to the rest of the network or is experiencing
 	// some other issue.
 	Ready bool `protobuf:"varint,5,opt,name=ready" json:"ready,omitempty"`
+	// MerkleRoot is the root hash of the Merkle tree over the hashes of the
+	// transactions up to LastIndex. It's only set if the ledger maintains a
+	// Merkle tree.
+	MerkleRoot []byte `protobuf:"bytes,6,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	// StateHash is the state hash of the ledger after the transaction at
+	// LastIndex.
+	StateHash []byte `protobuf:"bytes,7,opt,name=state_hash,json=stateHash,proto3" json:"state_hash,omitempty"`
+	// PublicKey is the Ed25519 public key the ledger node signs statuses and
+	// receipts with. It's only set if the node signs its responses.
+	PublicKey []byte `protobuf:"bytes,8,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// Signature is the Ed25519 signature of the node over the network seed,
+	// last index, state hash, Merkle root and server time of the status.
+	Signature []byte `protobuf:"bytes,9,opt,name=signature,proto3" json:"signature,omitempty"`
+	// SeedHistory lists the earlier network seeds of the ledger, oldest
+	// first, if it has been reset.
+	SeedHistory []*SeedReset `protobuf:"bytes,10,rep,name=seed_history,json=seedHistory" json:"seed_history,omitempty"`
 }
 
 func (m *ServerStatusResult) Reset()                    { *m = ServerStatusResult{} }
@@ -219,6 +278,223 @@ func (m *ServerStatusResult) String() string            { return proto.CompactTe
 func (*ServerStatusResult) ProtoMessage()               {}
 func (*ServerStatusResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
+func (m *ServerStatusResult) GetSeedHistory() []*SeedReset {
+	if m != nil {
+		return m.SeedHistory
+	}
+	return nil
+}
+
+// SubscribeRequest is a request to stream transactions from the ledger.
+type SubscribeRequest struct {
+	// NetworkSeed identifies the ledger. The subscription will be rejected if
+	// this is set and doesn't match what the ledger has.
+	NetworkSeed []byte `protobuf:"bytes,1,opt,name=network_seed,json=networkSeed,proto3" json:"network_seed,omitempty"`
+	// Index is the index of the first transaction to stream.
+	Index int64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	// Types, if set, restricts the stream to transactions matching one of
+	// these types, as for ReadRequest. Other transactions are skipped.
+	Types []string `protobuf:"bytes,3,rep,name=types" json:"types,omitempty"`
+}
+
+func (m *SubscribeRequest) Reset()                    { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()               {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+// GetTransactionRequest is a request to look up a transaction by its hash.
+type GetTransactionRequest struct {
+	// NetworkSeed identifies the ledger. The request will be rejected if this
+	// is set and doesn't match what the ledger has.
+	NetworkSeed []byte `protobuf:"bytes,1,opt,name=network_seed,json=networkSeed,proto3" json:"network_seed,omitempty"`
+	// Hash is the hash of the transaction to look up.
+	Hash []byte `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *GetTransactionRequest) Reset()                    { *m = GetTransactionRequest{} }
+func (m *GetTransactionRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetTransactionRequest) ProtoMessage()               {}
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+// GetTransactionResult is the result of a GetTransaction call.
+type GetTransactionResult struct {
+	// NetworkSeed identifies the ledger. It will always stay the same for a
+	// given ledger; if it has a surprising value, the transaction was looked
+	// up in a different (or potentially reset) ledger.
+	NetworkSeed []byte `protobuf:"bytes,1,opt,name=network_seed,json=networkSeed,proto3" json:"network_seed,omitempty"`
+	// Transaction is the sequenced transaction with the requested hash.
+	Transaction *SequencedTransaction `protobuf:"bytes,2,opt,name=transaction" json:"transaction,omitempty"`
+}
+
+func (m *GetTransactionResult) Reset()                    { *m = GetTransactionResult{} }
+func (m *GetTransactionResult) String() string            { return proto.CompactTextString(m) }
+func (*GetTransactionResult) ProtoMessage()               {}
+func (*GetTransactionResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *GetTransactionResult) GetTransaction() *SequencedTransaction {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
+}
+
+// Receipt tells where a transaction provided in an append request ended up on
+// the ledger.
+type Receipt struct {
+	// Index is the index the transaction was assigned on the ledger.
+	Index int64 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	// Hash is the SHA256 hash of the concatenation of type and data.
+	Hash []byte `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	// StateHash is the state hash of the ledger after the transaction.
+	StateHash []byte `protobuf:"bytes,3,opt,name=state_hash,json=stateHash,proto3" json:"state_hash,omitempty"`
+	// Timestamp is the time the transaction was appended to the ledger, in
+	// nanoseconds since the Unix epoch.
+	Timestamp int64 `protobuf:"varint,4,opt,name=timestamp" json:"timestamp,omitempty"`
+	// Duplicate is set if the transaction wasn't appended because it's a
+	// duplicate of a transaction already on the ledger, which the rest of the
+	// receipt refers to.
+	Duplicate bool `protobuf:"varint,5,opt,name=duplicate" json:"duplicate,omitempty"`
+	// Signature is the Ed25519 signature of the ledger node over the network
+	// seed and the rest of the receipt. It's only set if the node signs its
+	// responses.
+	Signature []byte `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *Receipt) Reset()                    { *m = Receipt{} }
+func (m *Receipt) String() string            { return proto.CompactTextString(m) }
+func (*Receipt) ProtoMessage()               {}
+func (*Receipt) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+// ExpectedIndex is an index a request expects the ledger to be at.
+type ExpectedIndex struct {
+	// Index is the expected index.
+	Index int64 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+}
+
+func (m *ExpectedIndex) Reset()                    { *m = ExpectedIndex{} }
+func (m *ExpectedIndex) String() string            { return proto.CompactTextString(m) }
+func (*ExpectedIndex) ProtoMessage()               {}
+func (*ExpectedIndex) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+// GetInclusionProofRequest is a request for the proof that a transaction is
+// included in the Merkle tree of the ledger.
+type GetInclusionProofRequest struct {
+	// NetworkSeed identifies the ledger. The request will be rejected if this
+	// is set and doesn't match what the ledger has.
+	NetworkSeed []byte `protobuf:"bytes,1,opt,name=network_seed,json=networkSeed,proto3" json:"network_seed,omitempty"`
+	// Index is the index of the transaction.
+	Index int64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	// TreeSize is the size of the tree to prove the inclusion in. If not set,
+	// the tree up to the last index of the ledger is used.
+	TreeSize int64 `protobuf:"varint,3,opt,name=tree_size,json=treeSize" json:"tree_size,omitempty"`
+}
+
+func (m *GetInclusionProofRequest) Reset()                    { *m = GetInclusionProofRequest{} }
+func (m *GetInclusionProofRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetInclusionProofRequest) ProtoMessage()               {}
+func (*GetInclusionProofRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+// GetInclusionProofResult is the result of a GetInclusionProof call.
+type GetInclusionProofResult struct {
+	// NetworkSeed identifies the ledger. It will always stay the same for a
+	// given ledger; if it has a surprising value, the proof was served by a
+	// different (or potentially reset) ledger.
+	NetworkSeed []byte `protobuf:"bytes,1,opt,name=network_seed,json=networkSeed,proto3" json:"network_seed,omitempty"`
+	// Index is the index of the transaction.
+	Index int64 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	// TreeSize is the size of the tree the proof is for.
+	TreeSize int64 `protobuf:"varint,3,opt,name=tree_size,json=treeSize" json:"tree_size,omitempty"`
+	// Hash is the hash of the transaction.
+	Hash []byte `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+	// Root is the root hash of the tree.
+	Root []byte `protobuf:"bytes,5,opt,name=root,proto3" json:"root,omitempty"`
+	// AuditPath is the list of node hashes needed to calculate the root hash
+	// from the hash of the transaction, ordered from the leaf up.
+	AuditPath [][]byte `protobuf:"bytes,6,rep,name=audit_path,json=auditPath,proto3" json:"audit_path,omitempty"`
+}
+
+func (m *GetInclusionProofResult) Reset()                    { *m = GetInclusionProofResult{} }
+func (m *GetInclusionProofResult) String() string            { return proto.CompactTextString(m) }
+func (*GetInclusionProofResult) ProtoMessage()               {}
+func (*GetInclusionProofResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+// GetConsistencyProofRequest is a request for the proof that the Merkle tree
+// of the ledger has only been appended to between two sizes.
+type GetConsistencyProofRequest struct {
+	// NetworkSeed identifies the ledger. The request will be rejected if this
+	// is set and doesn't match what the ledger has.
+	NetworkSeed []byte `protobuf:"bytes,1,opt,name=network_seed,json=networkSeed,proto3" json:"network_seed,omitempty"`
+	// From is the size of the older tree.
+	From int64 `protobuf:"varint,2,opt,name=from" json:"from,omitempty"`
+	// To is the size of the newer tree. If not set, the tree up to the last
+	// index of the ledger is used.
+	To int64 `protobuf:"varint,3,opt,name=to" json:"to,omitempty"`
+}
+
+func (m *GetConsistencyProofRequest) Reset()                    { *m = GetConsistencyProofRequest{} }
+func (m *GetConsistencyProofRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetConsistencyProofRequest) ProtoMessage()               {}
+func (*GetConsistencyProofRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+// GetConsistencyProofResult is the result of a GetConsistencyProof call.
+type GetConsistencyProofResult struct {
+	// NetworkSeed identifies the ledger. It will always stay the same for a
+	// given ledger; if it has a surprising value, the proof was served by a
+	// different (or potentially reset) ledger.
+	NetworkSeed []byte `protobuf:"bytes,1,opt,name=network_seed,json=networkSeed,proto3" json:"network_seed,omitempty"`
+	// From is the size of the older tree.
+	From int64 `protobuf:"varint,2,opt,name=from" json:"from,omitempty"`
+	// To is the size of the newer tree.
+	To int64 `protobuf:"varint,3,opt,name=to" json:"to,omitempty"`
+	// FromRoot is the root hash of the older tree.
+	FromRoot []byte `protobuf:"bytes,4,opt,name=from_root,json=fromRoot,proto3" json:"from_root,omitempty"`
+	// ToRoot is the root hash of the newer tree.
+	ToRoot []byte `protobuf:"bytes,5,opt,name=to_root,json=toRoot,proto3" json:"to_root,omitempty"`
+	// Proof is the list of node hashes needed to calculate both root hashes,
+	// as described in RFC 6962.
+	Proof [][]byte `protobuf:"bytes,6,rep,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (m *GetConsistencyProofResult) Reset()                    { *m = GetConsistencyProofResult{} }
+func (m *GetConsistencyProofResult) String() string            { return proto.CompactTextString(m) }
+func (*GetConsistencyProofResult) ProtoMessage()               {}
+func (*GetConsistencyProofResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+// Envelope wraps the payload of a transaction together with the signature of
+// its author. It's marshalled and carried as the data of the transaction.
+type Envelope struct {
+	// PublicKey is the Ed25519 public key of the author.
+	PublicKey []byte `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// Signature is the Ed25519 signature of the author over the transaction
+	// type and the payload.
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	// Payload is the data of the transaction, as provided by the author.
+	Payload []byte `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+}
+
+func (m *Envelope) Reset()                    { *m = Envelope{} }
+func (m *Envelope) String() string            { return proto.CompactTextString(m) }
+func (*Envelope) ProtoMessage()               {}
+func (*Envelope) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+// SeedReset records a reset of the ledger, which wiped its transactions and
+// replaced its network seed.
+type SeedReset struct {
+	// NetworkSeed is the seed the ledger had before the reset.
+	NetworkSeed []byte `protobuf:"bytes,1,opt,name=network_seed,json=networkSeed,proto3" json:"network_seed,omitempty"`
+	// LastIndex is the index of the last transaction on the ledger when it
+	// was reset.
+	LastIndex int64 `protobuf:"varint,2,opt,name=last_index,json=lastIndex" json:"last_index,omitempty"`
+	// ResetTime is the time of the reset, in nanoseconds since the Unix
+	// epoch.
+	ResetTime int64 `protobuf:"varint,3,opt,name=reset_time,json=resetTime" json:"reset_time,omitempty"`
+}
+
+func (m *SeedReset) Reset()                    { *m = SeedReset{} }
+func (m *SeedReset) String() string            { return proto.CompactTextString(m) }
+func (*SeedReset) ProtoMessage()               {}
+func (*SeedReset) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
 func init() {
 	proto.RegisterType((*ReadRequest)(nil), "api.ReadRequest")
 	proto.RegisterType((*ReadResult)(nil), "api.ReadResult")
@@ -228,6 +504,17 @@ func init() {
 	proto.RegisterType((*AppendResult)(nil), "api.AppendResult")
 	proto.RegisterType((*Empty)(nil), "api.Empty")
 	proto.RegisterType((*ServerStatusResult)(nil), "api.ServerStatusResult")
+	proto.RegisterType((*SubscribeRequest)(nil), "api.SubscribeRequest")
+	proto.RegisterType((*GetTransactionRequest)(nil), "api.GetTransactionRequest")
+	proto.RegisterType((*GetTransactionResult)(nil), "api.GetTransactionResult")
+	proto.RegisterType((*Receipt)(nil), "api.Receipt")
+	proto.RegisterType((*ExpectedIndex)(nil), "api.ExpectedIndex")
+	proto.RegisterType((*GetInclusionProofRequest)(nil), "api.GetInclusionProofRequest")
+	proto.RegisterType((*GetInclusionProofResult)(nil), "api.GetInclusionProofResult")
+	proto.RegisterType((*GetConsistencyProofRequest)(nil), "api.GetConsistencyProofRequest")
+	proto.RegisterType((*GetConsistencyProofResult)(nil), "api.GetConsistencyProofResult")
+	proto.RegisterType((*Envelope)(nil), "api.Envelope")
+	proto.RegisterType((*SeedReset)(nil), "api.SeedReset")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -254,6 +541,21 @@ type LedgerClient interface {
 	AppendTransactions(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResult, error)
 	// ServerStatus returns info about and status of the local ledger node.
 	ServerStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ServerStatusResult, error)
+	// SubscribeTransactions streams transactions from the ledger, starting at
+	// the requested index. Transactions already in the ledger are sent
+	// immediately, after which new ones are sent as they are sequenced. The
+	// stream stays open until the client cancels it or an error occurs.
+	SubscribeTransactions(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Ledger_SubscribeTransactionsClient, error)
+	// GetTransaction looks up a sequenced transaction by its hash. If the same
+	// transaction has been appended more than once, the first one is returned.
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResult, error)
+	// GetInclusionProof returns the audit path proving that a transaction is
+	// included in the Merkle tree over the transactions of the ledger.
+	GetInclusionProof(ctx context.Context, in *GetInclusionProofRequest, opts ...grpc.CallOption) (*GetInclusionProofResult, error)
+	// GetConsistencyProof returns the proof that the Merkle tree of one size
+	// is a prefix of the tree of a larger size, ie. that the ledger has only
+	// appended transactions in between.
+	GetConsistencyProof(ctx context.Context, in *GetConsistencyProofRequest, opts ...grpc.CallOption) (*GetConsistencyProofResult, error)
 }
 
 type ledgerClient struct {
@@ -291,6 +593,65 @@ func (c *ledgerClient) ServerStatus(ctx context.Context, in *Empty, opts ...grpc
 	return out, nil
 }
 
+func (c *ledgerClient) SubscribeTransactions(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Ledger_SubscribeTransactionsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Ledger_serviceDesc.Streams[0], c.cc, "/api.Ledger/SubscribeTransactions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ledgerSubscribeTransactionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Ledger_SubscribeTransactionsClient interface {
+	Recv() (*SequencedTransaction, error)
+	grpc.ClientStream
+}
+
+type ledgerSubscribeTransactionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *ledgerSubscribeTransactionsClient) Recv() (*SequencedTransaction, error) {
+	m := new(SequencedTransaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *ledgerClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResult, error) {
+	out := new(GetTransactionResult)
+	err := grpc.Invoke(ctx, "/api.Ledger/GetTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerClient) GetInclusionProof(ctx context.Context, in *GetInclusionProofRequest, opts ...grpc.CallOption) (*GetInclusionProofResult, error) {
+	out := new(GetInclusionProofResult)
+	err := grpc.Invoke(ctx, "/api.Ledger/GetInclusionProof", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerClient) GetConsistencyProof(ctx context.Context, in *GetConsistencyProofRequest, opts ...grpc.CallOption) (*GetConsistencyProofResult, error) {
+	out := new(GetConsistencyProofResult)
+	err := grpc.Invoke(ctx, "/api.Ledger/GetConsistencyProof", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Ledger service
 
 type LedgerServer interface {
@@ -307,6 +668,21 @@ type LedgerServer interface {
 	AppendTransactions(context.Context, *AppendRequest) (*AppendResult, error)
 	// ServerStatus returns info about and status of the local ledger node.
 	ServerStatus(context.Context, *Empty) (*ServerStatusResult, error)
+	// SubscribeTransactions streams transactions from the ledger, starting at
+	// the requested index. Transactions already in the ledger are sent
+	// immediately, after which new ones are sent as they are sequenced. The
+	// stream stays open until the client cancels it or an error occurs.
+	SubscribeTransactions(*SubscribeRequest, Ledger_SubscribeTransactionsServer) error
+	// GetTransaction looks up a sequenced transaction by its hash. If the same
+	// transaction has been appended more than once, the first one is returned.
+	GetTransaction(context.Context, *GetTransactionRequest) (*GetTransactionResult, error)
+	// GetInclusionProof returns the audit path proving that a transaction is
+	// included in the Merkle tree over the transactions of the ledger.
+	GetInclusionProof(context.Context, *GetInclusionProofRequest) (*GetInclusionProofResult, error)
+	// GetConsistencyProof returns the proof that the Merkle tree of one size
+	// is a prefix of the tree of a larger size, ie. that the ledger has only
+	// appended transactions in between.
+	GetConsistencyProof(context.Context, *GetConsistencyProofRequest) (*GetConsistencyProofResult, error)
 }
 
 func RegisterLedgerServer(s *grpc.Server, srv LedgerServer) {
@@ -367,6 +743,81 @@ func _Ledger_ServerStatus_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Ledger_SubscribeTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LedgerServer).SubscribeTransactions(m, &ledgerSubscribeTransactionsServer{stream})
+}
+
+type Ledger_SubscribeTransactionsServer interface {
+	Send(*SequencedTransaction) error
+	grpc.ServerStream
+}
+
+type ledgerSubscribeTransactionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *ledgerSubscribeTransactionsServer) Send(m *SequencedTransaction) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Ledger_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Ledger/GetTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetTransaction(ctx, req.(*GetTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ledger_GetInclusionProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInclusionProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetInclusionProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Ledger/GetInclusionProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetInclusionProof(ctx, req.(*GetInclusionProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ledger_GetConsistencyProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConsistencyProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetConsistencyProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.Ledger/GetConsistencyProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetConsistencyProof(ctx, req.(*GetConsistencyProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Ledger_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "api.Ledger",
 	HandlerType: (*LedgerServer)(nil),
@@ -383,41 +834,93 @@ var _Ledger_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ServerStatus",
 			Handler:    _Ledger_ServerStatus_Handler,
 		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _Ledger_GetTransaction_Handler,
+		},
+		{
+			MethodName: "GetInclusionProof",
+			Handler:    _Ledger_GetInclusionProof_Handler,
+		},
+		{
+			MethodName: "GetConsistencyProof",
+			Handler:    _Ledger_GetConsistencyProof_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeTransactions",
+			Handler:       _Ledger_SubscribeTransactions_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: fileDescriptor0,
 }
 
 func init() { proto.RegisterFile("api.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 441 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x94, 0xc1, 0x6f, 0x9b, 0x30,
-	0x14, 0xc6, 0x45, 0x48, 0xb2, 0xf1, 0x60, 0x5a, 0x66, 0x45, 0x1b, 0xcb, 0x36, 0x6d, 0xe3, 0x94,
-	0x53, 0x0e, 0x89, 0x76, 0x9a, 0xa6, 0x69, 0x87, 0x49, 0xad, 0xd4, 0x43, 0x65, 0xe8, 0xad, 0x12,
-	0x72, 0x83, 0xd5, 0xa0, 0x26, 0x86, 0x62, 0xd3, 0x36, 0x7f, 0x50, 0xef, 0xed, 0x7f, 0x58, 0xfb,
-	0x91, 0x28, 0x10, 0x51, 0x35, 0xbd, 0xd9, 0x3f, 0x7f, 0xb6, 0xdf, 0xf7, 0x3d, 0x03, 0x38, 0x2c,
-	0x4f, 0x27, 0x79, 0x91, 0xa9, 0x8c, 0xd8, 0x7a, 0x18, 0x9c, 0x83, 0x4b, 0x39, 0x4b, 0x28, 0xbf,
-	0x2e, 0xb9, 0x54, 0xe4, 0x27, 0x78, 0x82, 0xab, 0xdb, 0xac, 0xb8, 0x8a, 0x25, 0xe7, 0x89, 0x6f,
-	0xfd, 0xb0, 0xc6, 0x1e, 0x75, 0x37, 0x2c, 0xd4, 0x88, 0x0c, 0xa1, 0x97, 0x8a, 0x84, 0xdf, 0xf9,
-	0x1d, 0xbd, 0x66, 0xd3, 0x6a, 0x62, 0xe8, 0x3c, 0x2b, 0x85, 0xf2, 0xed, 0x8a, 0xe2, 0x24, 0x10,
-	0x00, 0xd5, 0xe9, 0xb2, 0x5c, 0x1e, 0x74, 0xf8, 0x1f, 0xf0, 0x54, 0xc1, 0x84, 0x64, 0x73, 0x95,
-	0x66, 0x42, 0xea, 0x3b, 0xec, 0xb1, 0x3b, 0xfd, 0x3c, 0x31, 0x55, 0x87, 0xa6, 0x46, 0x31, 0xe7,
-	0x49, 0xb4, 0x53, 0xd0, 0x86, 0x3c, 0xb8, 0xb7, 0x60, 0xd8, 0x26, 0x23, 0x04, 0xba, 0x6a, 0x9d,
-	0x73, 0xbc, 0xd2, 0xa1, 0x38, 0x7e, 0xc6, 0xc8, 0x57, 0x70, 0x54, 0xba, 0xd2, 0x59, 0xb0, 0x55,
-	0xbe, 0x31, 0xb3, 0x03, 0xe6, 0x9c, 0x84, 0x29, 0xe6, 0x77, 0xb1, 0x74, 0x1c, 0x1b, 0xb6, 0x60,
-	0x72, 0xe1, 0xf7, 0x2a, 0x66, 0xc6, 0xe4, 0x1b, 0x80, 0xde, 0xa0, 0x78, 0x8c, 0x2b, 0x7d, 0x5c,
-	0x71, 0x90, 0x1c, 0x69, 0x10, 0x48, 0x78, 0xf7, 0x2f, 0xcf, 0xb9, 0x78, 0x4d, 0xee, 0x7f, 0x5b,
-	0xa3, 0xf9, 0x82, 0xd1, 0x9c, 0x09, 0xf9, 0x72, 0x38, 0x11, 0x7c, 0x6c, 0xd7, 0xb5, 0xa6, 0xb3,
-	0x75, 0xda, 0x69, 0x71, 0x6a, 0xef, 0x9c, 0x06, 0xa7, 0xe0, 0x6d, 0xad, 0x1c, 0xda, 0x64, 0x1d,
-	0xce, 0x92, 0x49, 0x15, 0xd7, 0xd3, 0x77, 0x0c, 0x39, 0x36, 0x20, 0x78, 0x03, 0xbd, 0xff, 0xab,
-	0x5c, 0xad, 0x83, 0x07, 0x0b, 0x48, 0xc8, 0x8b, 0x1b, 0x5e, 0x84, 0x3a, 0xb9, 0x52, 0x1e, 0x7e,
-	0x43, 0x4d, 0x82, 0xc6, 0x3a, 0x68, 0x6c, 0x2b, 0x89, 0x8c, 0xbf, 0x66, 0x11, 0xf6, 0x5e, 0x11,
-	0xe4, 0x3b, 0xb8, 0x12, 0xaf, 0x8e, 0x4d, 0xf3, 0xb1, 0xdf, 0x36, 0x85, 0x0a, 0x45, 0x9a, 0x98,
-	0xd7, 0x53, 0xe8, 0xa7, 0xbd, 0xc6, 0xb6, 0xbf, 0xa5, 0xd5, 0x64, 0xfa, 0x68, 0x41, 0xff, 0x84,
-	0x27, 0x97, 0xbc, 0x20, 0xbf, 0x60, 0x60, 0xde, 0x7e, 0x2d, 0x67, 0x49, 0x06, 0xd8, 0xad, 0xda,
-	0x07, 0x37, 0x7a, 0x5f, 0x23, 0xe8, 0xee, 0x37, 0x90, 0x2a, 0xcf, 0xc6, 0x46, 0x82, 0xb2, 0xc6,
-	0x9b, 0x19, 0x7d, 0x68, 0x30, 0xdc, 0x3c, 0x03, 0xaf, 0x1e, 0x18, 0x01, 0x94, 0x60, 0x9a, 0xa3,
-	0x4f, 0x9b, 0x8f, 0x68, 0x3f, 0xcf, 0x8b, 0x3e, 0xfe, 0x0e, 0x66, 0x4f, 0x01, 0x00, 0x00, 0xff,
-	0xff, 0xb9, 0xa2, 0xcb, 0xd4, 0x1b, 0x04, 0x00, 0x00,
+	// 986 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x56, 0xd1, 0x6e, 0xdb, 0x36,
+	0x14, 0x85, 0x24, 0xc7, 0xb6, 0xae, 0xdd, 0x2c, 0x61, 0x93, 0x45, 0x71, 0x93, 0x35, 0x13, 0x30,
+	0xc0, 0x4f, 0xc5, 0x96, 0x62, 0x4f, 0xc5, 0x30, 0x6c, 0x43, 0x90, 0x16, 0x2d, 0x86, 0x40, 0xf6,
+	0xf6, 0x2a, 0x30, 0xd2, 0x6d, 0x2d, 0xc4, 0x16, 0x35, 0x92, 0xca, 0xea, 0xfc, 0xc3, 0x3e, 0x63,
+	0x6f, 0xc3, 0x1e, 0xb7, 0xdf, 0xd9, 0x2f, 0xec, 0x0f, 0x06, 0x92, 0x92, 0x23, 0xa9, 0x72, 0xe6,
+	0xa0, 0x79, 0x13, 0x0f, 0x2f, 0x79, 0x2f, 0x0f, 0xcf, 0x3d, 0x14, 0xb8, 0x34, 0x4b, 0x9e, 0x65,
+	0x9c, 0x49, 0x46, 0x1c, 0x9a, 0x25, 0x3e, 0x87, 0x41, 0x80, 0x34, 0x0e, 0xf0, 0x97, 0x1c, 0x85,
+	0x24, 0x9f, 0xc3, 0x30, 0x45, 0xf9, 0x2b, 0xe3, 0x57, 0xa1, 0x40, 0x8c, 0x3d, 0xeb, 0xc4, 0x1a,
+	0x0f, 0x83, 0x41, 0x81, 0x4d, 0x10, 0x63, 0xb2, 0x07, 0x5b, 0x49, 0x1a, 0xe3, 0x7b, 0xcf, 0x3e,
+	0xb1, 0xc6, 0x4e, 0x60, 0x06, 0x0a, 0x8d, 0x58, 0x9e, 0x4a, 0xcf, 0x31, 0xa8, 0x1e, 0x28, 0x54,
+	0x2e, 0x33, 0x14, 0x5e, 0xe7, 0xc4, 0x19, 0xbb, 0x81, 0x19, 0xf8, 0xbf, 0x59, 0x00, 0x26, 0xa9,
+	0xc8, 0xe7, 0x1b, 0xe5, 0xfc, 0x06, 0x86, 0x92, 0xd3, 0x54, 0xd0, 0x48, 0x26, 0x2c, 0x15, 0x9e,
+	0x7d, 0xe2, 0x8c, 0x07, 0xa7, 0x87, 0xcf, 0xd4, 0x61, 0x26, 0xaa, 0xf4, 0x34, 0xc2, 0x78, 0x7a,
+	0x1b, 0x11, 0xd4, 0xc2, 0xc9, 0x31, 0xc0, 0x9c, 0x0a, 0x19, 0x9a, 0xba, 0x4d, 0x85, 0xae, 0x42,
+	0x5e, 0x29, 0xc0, 0xff, 0xdd, 0x82, 0xbd, 0xb6, 0x5d, 0x08, 0x81, 0x8e, 0xaa, 0x58, 0x57, 0xe4,
+	0x06, 0xfa, 0x7b, 0xcd, 0xf1, 0x8f, 0xc0, 0x95, 0xc9, 0x02, 0x85, 0xa4, 0x8b, 0xac, 0x4c, 0xb0,
+	0x02, 0xd4, 0x3e, 0x31, 0x95, 0xd4, 0xeb, 0xe8, 0x93, 0xe9, 0x6f, 0x85, 0xcd, 0xa8, 0x98, 0x79,
+	0x5b, 0x06, 0x53, 0xdf, 0xaa, 0x4e, 0x21, 0xa9, 0xc4, 0x50, 0xcf, 0x74, 0xf5, 0x8c, 0xab, 0x91,
+	0x97, 0x54, 0xcc, 0xfc, 0xbf, 0x2d, 0x78, 0xf4, 0x5d, 0x96, 0x61, 0x7a, 0x9f, 0xeb, 0xfa, 0xb6,
+	0x95, 0xba, 0x27, 0x9a, 0xba, 0x9f, 0x52, 0xf1, 0xff, 0xe4, 0x7d, 0x0f, 0x8f, 0xf1, 0x7d, 0x86,
+	0x91, 0xc4, 0x38, 0x6c, 0xb0, 0x38, 0x38, 0x25, 0x7a, 0x9f, 0xb3, 0x62, 0x5e, 0xd3, 0x19, 0xec,
+	0x96, 0xe1, 0x6f, 0x56, 0x0c, 0x4f, 0xe1, 0xd3, 0xf6, 0x5c, 0xad, 0x14, 0x97, 0x74, 0xd9, 0x2d,
+	0x74, 0x39, 0xb7, 0x74, 0xf9, 0x37, 0x30, 0x2c, 0xe9, 0xd8, 0x54, 0x48, 0x75, 0x25, 0xd8, 0x0d,
+	0x25, 0x90, 0x31, 0xf4, 0x39, 0x46, 0x98, 0x64, 0x52, 0x78, 0x8e, 0x26, 0x6a, 0xa8, 0x0f, 0x18,
+	0x18, 0x30, 0x58, 0xcd, 0xfa, 0x3d, 0xd8, 0x3a, 0x5b, 0x64, 0x72, 0xe9, 0xff, 0x63, 0x03, 0x99,
+	0x20, 0xbf, 0x46, 0x3e, 0x91, 0x54, 0xe6, 0x62, 0xf3, 0x5a, 0x2a, 0x21, 0x9a, 0x02, 0x5b, 0x53,
+	0x50, 0x86, 0x4c, 0x15, 0x13, 0x77, 0x0b, 0x97, 0x3c, 0x85, 0x81, 0xd0, 0xa9, 0x43, 0xa5, 0x35,
+	0x2d, 0x2f, 0x27, 0x00, 0x03, 0x4d, 0x93, 0x85, 0x16, 0x2b, 0x47, 0x1a, 0x2f, 0xb5, 0xca, 0xfa,
+	0x81, 0x19, 0xa8, 0x65, 0x0b, 0xe4, 0x57, 0x73, 0x0c, 0x39, 0x63, 0xb2, 0xd0, 0x19, 0x18, 0x28,
+	0x60, 0x4c, 0x36, 0x74, 0xd8, 0x6b, 0xe8, 0x50, 0x4d, 0x67, 0xf9, 0xe5, 0x3c, 0x89, 0xc2, 0x2b,
+	0x5c, 0x7a, 0x7d, 0x33, 0x6d, 0x90, 0xd7, 0xb8, 0x54, 0xbd, 0x20, 0x92, 0x77, 0x29, 0x95, 0x39,
+	0x47, 0xcf, 0x2d, 0x16, 0x97, 0x00, 0xf9, 0x0a, 0x86, 0x8a, 0x90, 0x70, 0x96, 0x08, 0xc9, 0xf8,
+	0xd2, 0x03, 0x4d, 0xf3, 0x76, 0xd1, 0xca, 0xa8, 0xee, 0x12, 0x65, 0x30, 0x50, 0x31, 0x2f, 0x4d,
+	0x88, 0x4f, 0x61, 0x67, 0x92, 0x5f, 0x8a, 0x88, 0x27, 0x97, 0xf8, 0x10, 0x46, 0x65, 0x2c, 0xc9,
+	0xa9, 0x5a, 0xd2, 0x8f, 0xb0, 0x7f, 0x8e, 0xb2, 0xda, 0x04, 0x9b, 0xe7, 0x29, 0xa5, 0x69, 0x57,
+	0xa4, 0x79, 0x0d, 0x7b, 0xcd, 0xfd, 0x36, 0x95, 0xc5, 0x0b, 0x18, 0x54, 0xfa, 0x4f, 0xef, 0x7a,
+	0xa7, 0xd5, 0x55, 0xa3, 0xfd, 0x3f, 0x2c, 0xe8, 0x15, 0x62, 0xbd, 0x3d, 0xbf, 0x55, 0x3d, 0x7f,
+	0x4b, 0xb5, 0x8d, 0xfb, 0x76, 0x9a, 0xf7, 0x5d, 0x33, 0xb7, 0x4e, 0xd3, 0xdc, 0x8e, 0xc0, 0x8d,
+	0xf3, 0x6c, 0x9e, 0x44, 0x54, 0x62, 0xa1, 0xb3, 0x5b, 0xa0, 0x2e, 0x86, 0x6e, 0x43, 0x0c, 0xfe,
+	0x17, 0xf0, 0xa8, 0xe6, 0x1d, 0xed, 0x35, 0xfb, 0x19, 0x78, 0xe7, 0x28, 0x5f, 0xa5, 0xd1, 0x3c,
+	0x17, 0x09, 0x4b, 0x2f, 0x38, 0x63, 0x6f, 0x3f, 0x5a, 0x08, 0x4f, 0xc0, 0x95, 0x1c, 0x31, 0x14,
+	0xc9, 0x0d, 0x16, 0xad, 0xd5, 0x57, 0xc0, 0x24, 0xb9, 0x41, 0xff, 0x2f, 0x0b, 0x0e, 0x5a, 0x52,
+	0x6e, 0x7a, 0x87, 0xf7, 0xcf, 0xb8, 0xba, 0x97, 0x4e, 0xe5, 0x5e, 0x08, 0x74, 0x74, 0x87, 0x16,
+	0x6f, 0x04, 0x2f, 0x7a, 0x93, 0xe6, 0x71, 0x22, 0xc3, 0x8c, 0x4a, 0xf5, 0x46, 0x38, 0x8a, 0x51,
+	0x8d, 0x5c, 0x50, 0x39, 0xf3, 0x23, 0x18, 0x9d, 0xa3, 0xfc, 0x81, 0xa5, 0x22, 0x11, 0x12, 0xd3,
+	0x68, 0x79, 0x5f, 0xb2, 0x08, 0x74, 0xde, 0x72, 0xb6, 0x28, 0x2a, 0xd7, 0xdf, 0x64, 0x1b, 0x6c,
+	0xc9, 0x8a, 0x8a, 0x6d, 0xc9, 0xfc, 0x3f, 0x2d, 0x38, 0x6c, 0xcd, 0xb2, 0x29, 0x3f, 0x1b, 0x24,
+	0x51, 0x6c, 0x29, 0xdc, 0x78, 0x94, 0x61, 0xa5, 0xaf, 0x00, 0xed, 0x50, 0x07, 0xd0, 0x93, 0x2c,
+	0xac, 0x90, 0xd3, 0x95, 0x4c, 0x4f, 0xec, 0xc1, 0x56, 0xa6, 0x6a, 0x29, 0x98, 0x31, 0x03, 0x9f,
+	0x42, 0xff, 0x2c, 0xbd, 0xc6, 0x39, 0x33, 0x9e, 0x5a, 0x71, 0x2f, 0xeb, 0x4e, 0xf7, 0xb2, 0x9b,
+	0xee, 0xe5, 0x41, 0x2f, 0xa3, 0xcb, 0x39, 0xa3, 0x71, 0xd1, 0x26, 0xe5, 0xd0, 0x9f, 0x83, 0xbb,
+	0xb2, 0xaf, 0x07, 0x78, 0x89, 0x8e, 0x01, 0xb8, 0xda, 0xca, 0x38, 0x7b, 0xe1, 0xfc, 0x1a, 0x51,
+	0xc6, 0x7e, 0xfa, 0xaf, 0x03, 0xdd, 0x37, 0x18, 0xbf, 0x43, 0x4e, 0xbe, 0x86, 0x1d, 0xf5, 0x33,
+	0x35, 0xad, 0xbe, 0xd9, 0x3b, 0xc5, 0xab, 0xb5, 0xfa, 0xb1, 0x1b, 0x7d, 0x52, 0x41, 0xf4, 0x2d,
+	0xbd, 0x00, 0x62, 0x1e, 0xcf, 0xda, 0x42, 0xf3, 0x9e, 0xd7, 0x7e, 0x32, 0x46, 0xbb, 0x35, 0x4c,
+	0x2f, 0x7e, 0x0e, 0xc3, 0xea, 0x9b, 0x47, 0xc0, 0xfc, 0x06, 0xa8, 0x07, 0x71, 0x74, 0x50, 0x58,
+	0xd5, 0x07, 0x4f, 0xe2, 0x6b, 0xd8, 0x5f, 0xd9, 0x78, 0x2d, 0xe9, 0xbe, 0x59, 0xd1, 0xb0, 0xf8,
+	0xd1, 0x7a, 0xcf, 0xfb, 0xd2, 0x22, 0xe7, 0xb0, 0x5d, 0x37, 0x58, 0x32, 0xd2, 0xe1, 0xad, 0x2e,
+	0x3e, 0x3a, 0x6c, 0x9d, 0xd3, 0x55, 0x5d, 0xc0, 0xee, 0x07, 0x8d, 0x4e, 0x8e, 0xcb, 0xf8, 0x56,
+	0xcf, 0x19, 0x1d, 0xad, 0x9b, 0xd6, 0x3b, 0xfe, 0x0c, 0x8f, 0x5b, 0x9a, 0x83, 0x3c, 0x2d, 0x17,
+	0xad, 0x69, 0xce, 0xd1, 0x67, 0xeb, 0x03, 0xd4, 0xbe, 0x97, 0x5d, 0xfd, 0xdb, 0xfe, 0xfc, 0xbf,
+	0x01, 0x00, 0x62, 0x7d, 0x3f, 0xba, 0xc3, 0x0b, 0x00, 0x00,
 }
@@ -0,0 +1,19 @@
+package api
+
+import "golang.org/x/net/context"
+
+// asyncKey is the context key marking asynchronous appends.
+type asyncKey struct{}
+
+// WithAsync returns a copy of the context marking an append request as
+// asynchronous, ie. the caller doesn't wait for the result and any error is
+// lost. Ledgers may use it to handle such appends differently.
+func WithAsync(ctx context.Context) context.Context {
+	return context.WithValue(ctx, asyncKey{}, true)
+}
+
+// IsAsync returns true if the context was marked by WithAsync.
+func IsAsync(ctx context.Context) bool {
+	async, _ := ctx.Value(asyncKey{}).(bool)
+	return async
+}
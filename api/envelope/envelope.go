@@ -0,0 +1,54 @@
+// Package envelope implements the signed envelopes transaction payloads can be
+// wrapped in. It's shared by servers checking the signatures of appended
+// transactions and clients sealing and verifying them.
+//
+// A signed payload is wrapped in an api.Envelope, which is marshalled as the
+// data of the transaction. The signature covers the transaction type as well
+// as the payload, so that a signed payload can't be replayed under another
+// type.
+package envelope
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
+
+// signaturePrefix is the prefix of the data signed by authors, so that the
+// signature can't be passed off as one over a different kind of message.
+const signaturePrefix = "symbiont-envelope-v1"
+
+// SignedData returns the data covered by the signature of an envelope holding
+// the payload of a transaction of the provided type.
+func SignedData(typ string, payload []byte) []byte {
+	b := bytes.NewBufferString(signaturePrefix)
+	binary.Write(b, binary.BigEndian, uint32(len(typ)))
+	b.WriteString(typ)
+	binary.Write(b, binary.BigEndian, uint32(len(payload)))
+	b.Write(payload)
+	return b.Bytes()
+}
+
+// Open unmarshals the envelope in the data of a transaction of the provided
+// type and verifies its signature. The returned envelope holds the public key
+// of the author along with the payload.
+func Open(typ string, data []byte) (*api.Envelope, error) {
+	var e api.Envelope
+	if err := proto.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal envelope: %v", err)
+	}
+	if len(e.Signature) == 0 {
+		return nil, fmt.Errorf("Transaction is not signed")
+	}
+	if len(e.PublicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("Invalid public key length %d", len(e.PublicKey))
+	}
+	if !ed25519.Verify(e.PublicKey, SignedData(typ, e.Payload), e.Signature) {
+		return nil, fmt.Errorf("Invalid transaction signature")
+	}
+	return &e, nil
+}
@@ -0,0 +1,38 @@
+package envelope_test
+
+import (
+	"crypto/rand"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/api/envelope"
+
+	"github.com/nbio/st"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	st.Assert(t, err, nil)
+	data, err := proto.Marshal(&api.Envelope{
+		PublicKey: pub,
+		Signature: ed25519.Sign(key, envelope.SignedData("orders/new", []byte("payload"))),
+		Payload:   []byte("payload"),
+	})
+	st.Assert(t, err, nil)
+
+	e, err := envelope.Open("orders/new", data)
+	st.Assert(t, err, nil)
+	st.Expect(t, e.Payload, []byte("payload"))
+
+	// The signature covers the type.
+	_, err = envelope.Open("orders/cancel", data)
+	st.Reject(t, err, nil)
+
+	// Envelopes without a signature don't verify.
+	unsigned, err := proto.Marshal(&api.Envelope{PublicKey: pub, Payload: []byte("payload")})
+	st.Assert(t, err, nil)
+	_, err = envelope.Open("orders/new", unsigned)
+	st.Reject(t, err, nil)
+}
@@ -51,3 +51,44 @@ func (e ServerError) Error() string {
 }
 func (e ServerError) Timeout() bool   { return false }
 func (e ServerError) Temporary() bool { return true }
+
+// ConflictError is the error returned when a conditional append is rejected
+// because the last index of the ledger doesn't match the expected one, meaning
+// that other transactions have been appended concurrently.
+type ConflictError int64
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("Last index mismatch, ledger is at index %d", int64(e))
+}
+func (e ConflictError) Timeout() bool   { return false }
+func (e ConflictError) Temporary() bool { return false }
+
+// LastIndex returns the last index of the ledger when the append was rejected.
+// The caller may catch up to this index and retry the append with it as the
+// expected last index.
+func (e ConflictError) LastIndex() int64 {
+	return int64(e)
+}
+
+// NotEmptyError is the error returned when a history is imported into a ledger
+// that already holds transactions. Only empty ledgers can be imported into.
+type NotEmptyError int64
+
+func (e NotEmptyError) Error() string {
+	return fmt.Sprintf("Ledger is not empty, it's at index %d", int64(e))
+}
+func (e NotEmptyError) Timeout() bool   { return false }
+func (e NotEmptyError) Temporary() bool { return false }
+
+// LastIndex returns the last index of the ledger when the import was rejected.
+func (e NotEmptyError) LastIndex() int64 {
+	return int64(e)
+}
+
+// NotImplementedError is the error returned when the ledger doesn't support a
+// request, eg. a ledger forwarding requests to one that can't be reset.
+type NotImplementedError string
+
+func (e NotImplementedError) Error() string   { return string(e) }
+func (e NotImplementedError) Timeout() bool   { return false }
+func (e NotImplementedError) Temporary() bool { return false }
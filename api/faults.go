@@ -0,0 +1,165 @@
+package api
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// LatencyDistribution is the name of a latency distribution.
+type LatencyDistribution string
+
+// Supported latency distributions.
+const (
+	// ConstantLatency delays every request by Mean.
+	ConstantLatency LatencyDistribution = "constant"
+
+	// UniformLatency delays requests by between Min and Max.
+	UniformLatency LatencyDistribution = "uniform"
+
+	// NormalLatency delays requests following a normal distribution with
+	// Mean and StdDev. Negative delays are rounded up to zero.
+	NormalLatency LatencyDistribution = "normal"
+
+	// ExponentialLatency delays requests following an exponential
+	// distribution with Mean, which gives mostly short delays with a long
+	// tail.
+	ExponentialLatency LatencyDistribution = "exponential"
+)
+
+// Latency describes the distribution of delays a fault-injecting ledger adds
+// to requests. The zero value adds no delay.
+type Latency struct {
+	Distribution LatencyDistribution `json:"distribution,omitempty"`
+	Min          time.Duration       `json:"min,omitempty"`
+	Max          time.Duration       `json:"max,omitempty"`
+	Mean         time.Duration       `json:"mean,omitempty"`
+	StdDev       time.Duration       `json:"std_dev,omitempty"`
+}
+
+// ParseLatency parses a latency distribution in the form
+// `<distribution>:<duration>[,<duration>]`, where the durations are the mean
+// of constant and exponential distributions, the min and max of uniform ones,
+// and the mean and standard deviation of normal ones. Eg. `uniform:10ms,50ms`.
+func ParseLatency(s string) (Latency, error) {
+	var l Latency
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return l, fmt.Errorf("Invalid latency %q", s)
+	}
+	l.Distribution = LatencyDistribution(parts[0])
+	var ds []time.Duration
+	for _, p := range strings.Split(parts[1], ",") {
+		d, err := time.ParseDuration(p)
+		if err != nil {
+			return l, fmt.Errorf("Invalid latency %q: %v", s, err)
+		}
+		ds = append(ds, d)
+	}
+	want := 1
+	switch l.Distribution {
+	case ConstantLatency, ExponentialLatency:
+		l.Mean = ds[0]
+	case UniformLatency:
+		want = 2
+		l.Min, l.Max = ds[0], ds[len(ds)-1]
+	case NormalLatency:
+		want = 2
+		l.Mean, l.StdDev = ds[0], ds[len(ds)-1]
+	default:
+		return l, fmt.Errorf("Unknown latency distribution %q", l.Distribution)
+	}
+	if len(ds) != want {
+		return l, fmt.Errorf("Invalid latency %q: %s takes %d durations", s, l.Distribution, want)
+	}
+	return l, l.validate()
+}
+
+// validate checks that the latency is a known distribution with valid
+// parameters.
+func (l Latency) validate() error {
+	if l.Min < 0 || l.Max < 0 || l.Mean < 0 || l.StdDev < 0 {
+		return fmt.Errorf("Negative latency")
+	}
+	switch l.Distribution {
+	case "", ConstantLatency, NormalLatency, ExponentialLatency:
+	case UniformLatency:
+		if l.Min > l.Max {
+			return fmt.Errorf("Latency min is larger than max")
+		}
+	default:
+		return fmt.Errorf("Unknown latency distribution %q", l.Distribution)
+	}
+	return nil
+}
+
+// Sample draws a delay from the distribution.
+func (l Latency) Sample(r *rand.Rand) time.Duration {
+	var d time.Duration
+	switch l.Distribution {
+	case ConstantLatency:
+		d = l.Mean
+	case UniformLatency:
+		d = l.Min + time.Duration(r.Int63n(int64(l.Max-l.Min)+1))
+	case NormalLatency:
+		d = l.Mean + time.Duration(r.NormFloat64()*float64(l.StdDev))
+	case ExponentialLatency:
+		d = time.Duration(r.ExpFloat64() * float64(l.Mean))
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// FaultConfig describes the faults injected into requests by a ledger wrapped
+// in a fault-injection layer, such as the one of the mock/faults package. The
+// zero value injects no faults. Rates are the probability, between 0 and 1, of
+// injecting a fault into each request.
+type FaultConfig struct {
+	// Latency is the distribution of delays added to every request before
+	// it's forwarded to the ledger.
+	Latency Latency `json:"latency"`
+
+	// ServerErrorRate is the rate at which requests fail with a ServerError
+	// instead of being forwarded.
+	ServerErrorRate float64 `json:"server_error_rate"`
+
+	// NotFoundRate is the rate at which reads, subscriptions and lookups fail
+	// with a NotFoundError instead of being forwarded.
+	NotFoundRate float64 `json:"not_found_rate"`
+
+	// AsyncDropRate is the rate at which asynchronous appends (see
+	// WithAsync) are dropped without being forwarded.
+	AsyncDropRate float64 `json:"async_drop_rate"`
+
+	// NotReady makes the ledger report itself as not ready in its status.
+	NotReady bool `json:"not_ready"`
+
+	// NotReadyEvery and NotReadyFor make the ledger periodically report
+	// itself as not ready: every NotReadyEvery since the config was set, it
+	// isn't ready for NotReadyFor.
+	NotReadyEvery time.Duration `json:"not_ready_every"`
+	NotReadyFor   time.Duration `json:"not_ready_for"`
+}
+
+// Validate checks that the rates are probabilities and that the latency and
+// not ready periods are valid.
+func (c FaultConfig) Validate() error {
+	for _, r := range []float64{c.ServerErrorRate, c.NotFoundRate, c.AsyncDropRate} {
+		if r < 0 || r > 1 {
+			return fmt.Errorf("Rate %v is not between 0 and 1", r)
+		}
+	}
+	if err := c.Latency.validate(); err != nil {
+		return err
+	}
+	if c.NotReadyEvery < 0 || c.NotReadyFor < 0 {
+		return fmt.Errorf("Negative not ready period")
+	}
+	if c.NotReadyFor > c.NotReadyEvery {
+		return fmt.Errorf("Not ready for longer than the period")
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"encoding/hex"
+	"golang.org/x/net/context"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"strconv"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
+
+// NetworkSeedKey is the metadata key used to hold the network seed of the
+// ledger in the trailer of a call rejected due to a network seed mismatch.
+// Successful calls carry the seed in the response message instead.
+const NetworkSeedKey = "symbiont-network-seed"
+
+// LastIndexKey is the metadata key used to hold the last index of the ledger
+// in the trailer of a conditional append rejected due to a conflict.
+const LastIndexKey = "symbiont-last-index"
+
+// EncodeError converts an error returned by the ledger into a gRPC error with
+// a matching status code. For network seed mismatches the correct seed is set
+// in the trailer of the call, under NetworkSeedKey, and for conflicts the last
+// index of the ledger is set under LastIndexKey.
+func EncodeError(ctx context.Context, err error) error {
+	switch err := err.(type) {
+	case api.BadRequestError:
+		return gogrpc.Errorf(codes.InvalidArgument, "%s", err)
+	case api.NotFoundError:
+		return gogrpc.Errorf(codes.NotFound, "%s", err)
+	case api.NetworkSeedMismatchError:
+		gogrpc.SetTrailer(ctx, metadata.Pairs(NetworkSeedKey, hex.EncodeToString(err.CorrectSeed())))
+		return gogrpc.Errorf(codes.FailedPrecondition, "%s", err)
+	case api.ConflictError:
+		gogrpc.SetTrailer(ctx, metadata.Pairs(LastIndexKey, strconv.FormatInt(err.LastIndex(), 10)))
+		return gogrpc.Errorf(codes.Aborted, "%s", err)
+	case api.ServerError:
+		return gogrpc.Errorf(codes.Internal, "%s", err)
+	}
+	switch err {
+	case context.Canceled:
+		return gogrpc.Errorf(codes.Canceled, "%v", err)
+	case context.DeadlineExceeded:
+		return gogrpc.Errorf(codes.DeadlineExceeded, "%v", err)
+	default:
+		return gogrpc.Errorf(codes.Unknown, "%v", err)
+	}
+}
+
+// DecodeError converts an error returned by a gRPC call back into the error
+// type the ledger originally returned, using the trailer of the call to
+// recover the network seed and last index. An unavailable server is reported
+// as a ServerError, so that it's seen as temporary. Other errors that don't
+// originate from the ledger are returned as is.
+func DecodeError(err error, trailer metadata.MD) error {
+	msg := gogrpc.ErrorDesc(err)
+	switch gogrpc.Code(err) {
+	case codes.InvalidArgument:
+		return api.BadRequestError(msg)
+	case codes.NotFound:
+		return api.NotFoundError(msg)
+	case codes.FailedPrecondition:
+		var seed []byte
+		if v := trailer[NetworkSeedKey]; len(v) > 0 {
+			seed, _ = hex.DecodeString(v[0])
+		}
+		return api.NetworkSeedMismatchError(seed)
+	case codes.Aborted:
+		var last int64
+		if v := trailer[LastIndexKey]; len(v) > 0 {
+			last, _ = strconv.ParseInt(v[0], 10, 64)
+		}
+		return api.ConflictError(last)
+	case codes.Internal, codes.Unavailable:
+		return api.ServerError(msg)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,151 @@
+// Package grpc provides a gRPC API for the ledger, using the service and
+// messages defined in api.proto. Errors are mapped to gRPC status codes.
+package grpc
+
+import (
+	"golang.org/x/net/context"
+	gogrpc "google.golang.org/grpc"
+	"net"
+	"time"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
+
+// Server is a gRPC API server for the ledger api. It takes requests and
+// forwards them to the provided ledger.
+type Server struct {
+	ledger  api.LedgerServer
+	options options
+	server  *gogrpc.Server
+}
+
+// NewServer create a new Server backed by the provided ledger.
+func NewServer(ledger api.LedgerServer, opt ...Option) *Server {
+	s := &Server{
+		ledger:  ledger,
+		options: defaultOptions,
+	}
+	for _, o := range opt {
+		o(&s.options)
+	}
+
+	s.server = gogrpc.NewServer()
+	api.RegisterLedgerServer(s.server, s)
+	return s
+}
+
+// GRPCServer returns the underlying grpc.Server, eg. for registering
+// additional services on it.
+func (s *Server) GRPCServer() *gogrpc.Server {
+	return s.server
+}
+
+// Serve accepts connections on the listener and serves requests on them. It
+// only returns when the listener fails or the server is stopped.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+// Stop stops the server, closing all listeners and connections.
+func (s *Server) Stop() {
+	s.server.Stop()
+}
+
+// ReadTransactions forwards read requests to the ledger. Missing or too large
+// counts are replaced by the configured defaults and limits. The ledger is
+// allowed to wait for new transactions for half the time left until the
+// deadline of the call, setting aside the other half for network overhead, or
+// for the default poll timeout if the client hasn't set a deadline.
+func (s *Server) ReadTransactions(ctx context.Context, req *api.ReadRequest) (*api.ReadResult, error) {
+	s.infof("Handling read request from index %d", req.Index)
+
+	r := *req
+	if r.Count <= 0 {
+		r.Count = s.options.defaultCount
+	}
+	if r.Count > s.options.maxCount {
+		r.Count = s.options.maxCount
+	}
+	pollTimeout := s.options.defaultPollTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		pollTimeout = deadline.Sub(time.Now()) / 2
+	}
+	pollCtx, cancel := context.WithTimeout(ctx, pollTimeout)
+	defer cancel()
+
+	res, err := s.ledger.ReadTransactions(pollCtx, &r)
+	if err != nil {
+		s.warnf("Read failed: %v", err)
+		return nil, EncodeError(ctx, err)
+	}
+	s.infof("Returning %d transactions, indexes [%d, %d)",
+		len(res.Transactions), r.Index, r.Index+int64(len(res.Transactions)))
+	return res, nil
+}
+
+// AppendTransactions forwards append requests to the ledger.
+func (s *Server) AppendTransactions(ctx context.Context, req *api.AppendRequest) (*api.AppendResult, error) {
+	s.debugf("Appending %d transactions", len(req.Transactions))
+	res, err := s.ledger.AppendTransactions(ctx, req)
+	if err != nil {
+		s.warnf("Append failed: %v", err)
+		return nil, EncodeError(ctx, err)
+	}
+	s.infof("Transactions appended with indexes ending at %d", res.LastIndex)
+	return res, nil
+}
+
+// ServerStatus forwards status requests to the ledger.
+func (s *Server) ServerStatus(ctx context.Context, req *api.Empty) (*api.ServerStatusResult, error) {
+	res, err := s.ledger.ServerStatus(ctx, req)
+	if err != nil {
+		s.warnf("Status request failed: %v", err)
+		return nil, EncodeError(ctx, err)
+	}
+	return res, nil
+}
+
+// SubscribeTransactions forwards subscriptions to the ledger, which streams
+// transactions directly to the client.
+func (s *Server) SubscribeTransactions(req *api.SubscribeRequest, stream api.Ledger_SubscribeTransactionsServer) error {
+	s.infof("Handling subscription from index %d", req.Index)
+	err := s.ledger.SubscribeTransactions(req, stream)
+	if err != nil {
+		s.infof("Subscription ended: %v", err)
+		return EncodeError(stream.Context(), err)
+	}
+	return nil
+}
+
+// GetTransaction forwards lookups of transactions by hash to the ledger.
+func (s *Server) GetTransaction(ctx context.Context, req *api.GetTransactionRequest) (*api.GetTransactionResult, error) {
+	s.infof("Looking up transaction %x", req.Hash)
+	res, err := s.ledger.GetTransaction(ctx, req)
+	if err != nil {
+		s.infof("Lookup failed: %v", err)
+		return nil, EncodeError(ctx, err)
+	}
+	return res, nil
+}
+
+// GetInclusionProof forwards requests for inclusion proofs to the ledger.
+func (s *Server) GetInclusionProof(ctx context.Context, req *api.GetInclusionProofRequest) (*api.GetInclusionProofResult, error) {
+	s.infof("Handling inclusion proof of transaction %d", req.Index)
+	res, err := s.ledger.GetInclusionProof(ctx, req)
+	if err != nil {
+		s.infof("Inclusion proof failed: %v", err)
+		return nil, EncodeError(ctx, err)
+	}
+	return res, nil
+}
+
+// GetConsistencyProof forwards requests for consistency proofs to the ledger.
+func (s *Server) GetConsistencyProof(ctx context.Context, req *api.GetConsistencyProofRequest) (*api.GetConsistencyProofResult, error) {
+	s.infof("Handling consistency proof from tree size %d to %d", req.From, req.To)
+	res, err := s.ledger.GetConsistencyProof(ctx, req)
+	if err != nil {
+		s.infof("Consistency proof failed: %v", err)
+		return nil, EncodeError(ctx, err)
+	}
+	return res, nil
+}
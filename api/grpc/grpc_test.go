@@ -0,0 +1,223 @@
+package grpc_test
+
+import (
+	"golang.org/x/net/context"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"net"
+	"time"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/api/grpc"
+	"github.com/symbiont-io/assembly-sdk/mock"
+
+	"github.com/nbio/st"
+	"github.com/symbiont-io/assembly-sdk/test/utils"
+	"testing"
+)
+
+// startServer serves the ledger on a local port and returns a client
+// connected to it, along with a function shutting both down.
+func startServer(t *testing.T, l api.LedgerServer, opt ...grpc.Option) (api.LedgerClient, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	st.Assert(t, err, nil)
+	s := grpc.NewServer(l, opt...)
+	go s.Serve(lis)
+
+	conn, err := gogrpc.Dial(lis.Addr().String(), gogrpc.WithInsecure())
+	st.Assert(t, err, nil)
+	return api.NewLedgerClient(conn), func() {
+		conn.Close()
+		s.Stop()
+	}
+}
+
+func TestServerAppendRead(t *testing.T) {
+	l := mock.NewLedger()
+	c, stop := startServer(t, l)
+	defer stop()
+
+	ctx := context.Background()
+	status, err := c.ServerStatus(ctx, &api.Empty{})
+	st.Assert(t, err, nil)
+	st.Expect(t, status.NetworkType, "mock")
+
+	txs := utils.RandomUnsequencedTransactions(5, 100)
+	appendRes, err := c.AppendTransactions(ctx, &api.AppendRequest{
+		NetworkSeed:  status.NetworkSeed,
+		Transactions: txs,
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, appendRes.LastIndex, int64(5))
+	st.Expect(t, appendRes.NetworkSeed, status.NetworkSeed)
+
+	readRes, err := c.ReadTransactions(ctx, &api.ReadRequest{
+		NetworkSeed: status.NetworkSeed,
+		Index:       2,
+	})
+	st.Assert(t, err, nil)
+	st.Assert(t, len(readRes.Transactions), 4)
+	for i, tx := range readRes.Transactions {
+		st.Expect(t, tx.Index, int64(2+i))
+		st.Expect(t, tx.Data, txs[1+i].Data)
+	}
+}
+
+func TestServerMaxCount(t *testing.T) {
+	l := mock.NewLedger()
+	c, stop := startServer(t, l, grpc.WithMaxCount(2))
+	defer stop()
+
+	ctx := context.Background()
+	_, err := c.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(5, 100),
+	})
+	st.Assert(t, err, nil)
+	res, err := c.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 10})
+	st.Assert(t, err, nil)
+	st.Expect(t, len(res.Transactions), 2)
+}
+
+func TestServerDefaultPollTimeout(t *testing.T) {
+	l := mock.NewLedger()
+	c, stop := startServer(t, l, grpc.WithDefaultPollTimeout(10*time.Millisecond))
+	defer stop()
+
+	res, err := c.ReadTransactions(context.Background(), &api.ReadRequest{Index: 1})
+	st.Assert(t, err, nil)
+	st.Expect(t, len(res.Transactions), 0)
+}
+
+func TestServerBadSeed(t *testing.T) {
+	l := mock.NewLedger()
+	c, stop := startServer(t, l)
+	defer stop()
+
+	ctx := context.Background()
+	status, _ := l.ServerStatus(ctx, nil)
+	var trailer metadata.MD
+	_, err := c.AppendTransactions(ctx, &api.AppendRequest{
+		NetworkSeed:  []byte("bad seed"),
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	}, gogrpc.Trailer(&trailer))
+	st.Refute(t, err, nil)
+	st.Expect(t, gogrpc.Code(err), codes.FailedPrecondition)
+
+	err = grpc.DecodeError(err, trailer)
+	seed, ok := err.(api.NetworkSeedMismatchError)
+	st.Assert(t, ok, true)
+	st.Expect(t, seed.CorrectSeed(), status.NetworkSeed)
+}
+
+func TestServerConflict(t *testing.T) {
+	l := mock.NewLedger()
+	c, stop := startServer(t, l)
+	defer stop()
+
+	ctx := context.Background()
+	var trailer metadata.MD
+	_, err := c.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions:      utils.RandomUnsequencedTransactions(1, 100),
+		ExpectedLastIndex: &api.ExpectedIndex{Index: 5},
+	}, gogrpc.Trailer(&trailer))
+	st.Expect(t, gogrpc.Code(err), codes.Aborted)
+	st.Expect(t, grpc.DecodeError(err, trailer), api.ConflictError(0))
+}
+
+func TestServerNotFound(t *testing.T) {
+	l := mock.NewLedger()
+	c, stop := startServer(t, l)
+	defer stop()
+
+	_, err := c.ReadTransactions(context.Background(), &api.ReadRequest{Index: 10})
+	st.Refute(t, err, nil)
+	st.Expect(t, gogrpc.Code(err), codes.NotFound)
+	_, ok := grpc.DecodeError(err, nil).(api.NotFoundError)
+	st.Assert(t, ok, true)
+}
+
+func TestErrorMapping(t *testing.T) {
+	ctx := context.Background()
+	for _, err := range []error{
+		api.BadRequestError("bad request"),
+		api.NotFoundError("not found"),
+		api.ServerError("server error"),
+	} {
+		decoded := grpc.DecodeError(grpc.EncodeError(ctx, err), nil)
+		st.Expect(t, decoded, err)
+	}
+}
+
+func TestServerSubscribe(t *testing.T) {
+	l := mock.NewLedger()
+	c, stop := startServer(t, l)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	appendTyped := func(types ...string) {
+		var txs []*api.UnsequencedTransaction
+		for _, typ := range types {
+			txs = append(txs, &api.UnsequencedTransaction{Type: typ, Data: []byte(typ)})
+		}
+		_, err := l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+		st.Assert(t, err, nil)
+	}
+	appendTyped("a", "b", "a")
+
+	stream, err := c.SubscribeTransactions(ctx, &api.SubscribeRequest{
+		Index: 2,
+		Types: []string{"a"},
+	})
+	st.Assert(t, err, nil)
+	tx, err := stream.Recv()
+	st.Assert(t, err, nil)
+	st.Expect(t, tx.Index, int64(3))
+
+	// New transactions are pushed as they are appended.
+	appendTyped("b", "a")
+	tx, err = stream.Recv()
+	st.Assert(t, err, nil)
+	st.Expect(t, tx.Index, int64(5))
+	st.Expect(t, tx.Type, "a")
+}
+
+func TestServerSubscribeBadSeed(t *testing.T) {
+	l := mock.NewLedger()
+	c, stop := startServer(t, l)
+	defer stop()
+
+	status, _ := l.ServerStatus(context.Background(), nil)
+	stream, err := c.SubscribeTransactions(context.Background(), &api.SubscribeRequest{
+		NetworkSeed: []byte("bad seed"),
+		Index:       1,
+	})
+	st.Assert(t, err, nil)
+	_, err = stream.Recv()
+	err = grpc.DecodeError(err, stream.Trailer())
+	seed, ok := err.(api.NetworkSeedMismatchError)
+	st.Assert(t, ok, true)
+	st.Expect(t, seed.CorrectSeed(), status.NetworkSeed)
+}
+
+func TestServerGetTransaction(t *testing.T) {
+	l := mock.NewLedger()
+	c, stop := startServer(t, l)
+	defer stop()
+
+	ctx := context.Background()
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(2, 100),
+	})
+	st.Assert(t, err, nil)
+	read, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 2})
+	st.Assert(t, err, nil)
+
+	res, err := c.GetTransaction(ctx, &api.GetTransactionRequest{Hash: read.Transactions[1].Hash})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.Transaction.Index, int64(2))
+
+	_, err = c.GetTransaction(ctx, &api.GetTransactionRequest{Hash: []byte("unknown")})
+	st.Expect(t, gogrpc.Code(err), codes.NotFound)
+}
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"time"
+)
+
+const (
+	DefaultCount       = 100
+	DefaultMaxCount    = 1000
+	DefaultPollTimeout = 5 * time.Second
+)
+
+type options struct {
+	defaultCount       int64
+	maxCount           int64
+	defaultPollTimeout time.Duration
+	logger             Logger
+}
+
+var defaultOptions = options{
+	defaultCount:       DefaultCount,
+	maxCount:           DefaultMaxCount,
+	defaultPollTimeout: DefaultPollTimeout,
+}
+
+type Option func(*options)
+
+func WithDefaultCount(c int64) Option {
+	return func(o *options) {
+		o.defaultCount = c
+	}
+}
+
+func WithMaxCount(c int64) Option {
+	return func(o *options) {
+		o.maxCount = c
+	}
+}
+
+func WithDefaultPollTimeout(to time.Duration) Option {
+	return func(o *options) {
+		o.defaultPollTimeout = to
+	}
+}
+
+func WithLogger(l Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
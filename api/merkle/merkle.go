@@ -0,0 +1,59 @@
+// Package merkle implements the hashing of the Merkle tree a ledger maintains
+// over the hashes of its transactions, as described in RFC 6962 (Certificate
+// Transparency). It's shared by ledgers building the tree and clients
+// verifying proofs against it.
+//
+// The hash of each transaction is a leaf, in index order. The tree of size N
+// covers the transactions with indexes 1 to N.
+package merkle
+
+import "crypto/sha256"
+
+// Prefixes used to separate leaf hashes from node hashes, so that a node can't
+// be passed off as a leaf.
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// LeafHash returns the hash of the leaf holding a transaction hash.
+func LeafHash(hash []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(hash)
+	return h.Sum(nil)
+}
+
+// NodeHash returns the hash of the node with the provided children.
+func NodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// RootHash calculates the root hash of the tree over the provided transaction
+// hashes. It's mostly useful for checking a root hash against transactions
+// that have been read from the ledger.
+func RootHash(hashes [][]byte) []byte {
+	if len(hashes) == 0 {
+		h := sha256.Sum256(nil)
+		return h[:]
+	}
+	if len(hashes) == 1 {
+		return LeafHash(hashes[0])
+	}
+	k := SplitPoint(int64(len(hashes)))
+	return NodeHash(RootHash(hashes[:k]), RootHash(hashes[k:]))
+}
+
+// SplitPoint returns the largest power of two smaller than n, which is where
+// a tree of size n is split into subtrees. n must be larger than 1.
+func SplitPoint(n int64) int64 {
+	k := int64(1)
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
+
+// authorize wraps an administrative request handler, rejecting requests that
+// don't hold the admin token in their Authorization header, if one is set.
+func (s *Server) authorize(fn func(http.ResponseWriter, *http.Request) error) func(http.ResponseWriter, *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		if s.options.adminToken == "" {
+			return fn(w, r)
+		}
+		auth := r.Header.Get("Authorization")
+		const prefix = "Bearer "
+		if !strings.HasPrefix(auth, prefix) || subtle.ConstantTimeCompare(
+			[]byte(strings.TrimPrefix(auth, prefix)), []byte(s.options.adminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			return &handleError{fmt.Errorf("missing or invalid admin token"),
+				"Unauthorized", http.StatusUnauthorized}
+		}
+		return fn(w, r)
+	}
+}
+
+// Importer is implemented by ledgers that can load a pre-sequenced history
+// into an empty ledger, such as mock.Ledger. Ledgers forwarding imports to
+// one that can't handle them return an api.NotImplementedError.
+type Importer interface {
+	ImportTransactions(seed []byte, next func() (*api.SequencedTransaction, error)) (int64, error)
+}
+
+// importHandler loads the history in the request body, in the format of an
+// export, into the empty ledger. The network seed of the history is taken from
+// the Symbiont-Network-Seed header.
+func (s *Server) importHandler(w http.ResponseWriter, r *http.Request) error {
+	importer, ok := s.ledger.(Importer)
+	if !ok {
+		return &handleError{fmt.Errorf("ledger doesn't support imports"),
+			"Failed to import transactions", http.StatusNotImplemented}
+	}
+	seed, err := hex.DecodeString(r.Header.Get(SymbiontNetworkSeedHeader))
+	if err != nil {
+		return &handleError{err, "Failed to parse network seed", http.StatusBadRequest}
+	}
+	body, err := decodeBody(r)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	// Decode transactions one line at a time, as the ledger asks for them.
+	// The decoder returns io.EOF at the end of the body, which ends the
+	// import.
+	dec := json.NewDecoder(body)
+	next := func() (*api.SequencedTransaction, error) {
+		var line TransactionResult
+		if err := dec.Decode(&line); err != nil {
+			return nil, err
+		}
+		if line.Error != "" {
+			return nil, fmt.Errorf("History holds an error: %s", line.Error)
+		}
+		if line.EncodedSequencedTransaction == nil {
+			return nil, fmt.Errorf("Missing transaction")
+		}
+		txs, err := DecodeSequencedTransactions(
+			[]*EncodedSequencedTransaction{line.EncodedSequencedTransaction})
+		if err != nil {
+			return nil, err
+		}
+		return txs[0], nil
+	}
+
+	s.infof("Importing transactions")
+	last, err := importer.ImportTransactions(seed, next)
+	if err != nil {
+		switch err := err.(type) {
+		case api.BadRequestError:
+			return &handleError{err, "Refused to import transactions", http.StatusBadRequest}
+		case api.NotEmptyError:
+			w.Header().Add(SymbiontLastIndexHeader, strconv.FormatInt(err.LastIndex(), 10))
+			return &handleError{err, "Ledger is not empty", http.StatusConflict}
+		case api.NotImplementedError:
+			return &handleError{err, "Failed to import transactions", http.StatusNotImplemented}
+		default:
+			return err
+		}
+	}
+
+	s.infof("Imported transactions up to index %d", last)
+	writeNetworkSeed(w, seed)
+	return json.NewEncoder(w).Encode(&ImportResult{LastIndex: last})
+}
+
+// FaultInjector is implemented by ledgers injecting faults that can be changed
+// at runtime, such as faults.Ledger.
+type FaultInjector interface {
+	Config() api.FaultConfig
+	SetConfig(api.FaultConfig) error
+}
+
+// faultsHandler returns the faults injected by the ledger, after replacing
+// them with those in the request body on PUT requests.
+func (s *Server) faultsHandler(w http.ResponseWriter, r *http.Request) error {
+	injector, ok := s.ledger.(FaultInjector)
+	if !ok {
+		return &handleError{fmt.Errorf("ledger doesn't inject faults"),
+			"Failed to handle faults", http.StatusNotImplemented}
+	}
+	if r.Method == "PUT" {
+		body, err := decodeBody(r)
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+		var c api.FaultConfig
+		if err := json.NewDecoder(body).Decode(&c); err != nil {
+			return &handleError{err, "Failed to parse body", http.StatusBadRequest}
+		}
+		if err := injector.SetConfig(c); err != nil {
+			return &handleError{err, "Refused to set faults", http.StatusBadRequest}
+		}
+		s.infof("Injecting faults: %+v", c)
+	}
+	return json.NewEncoder(w).Encode(injector.Config())
+}
+
+// Resetter is implemented by ledgers that can wipe their transactions and
+// replace their network seed, such as mock.Ledger. Ledgers forwarding resets
+// to one that can't handle them return an api.NotImplementedError.
+type Resetter interface {
+	Reset() ([]byte, error)
+}
+
+// resetHandler resets the ledger, returning its new network seed.
+func (s *Server) resetHandler(w http.ResponseWriter, r *http.Request) error {
+	resetter, ok := s.ledger.(Resetter)
+	if !ok {
+		return &handleError{fmt.Errorf("ledger doesn't support resets"),
+			"Failed to reset ledger", http.StatusNotImplemented}
+	}
+	seed, err := resetter.Reset()
+	if err != nil {
+		if _, ok := err.(api.NotImplementedError); ok {
+			return &handleError{err, "Failed to reset ledger", http.StatusNotImplemented}
+		}
+		return err
+	}
+
+	s.infof("Reset ledger with network seed %x", seed)
+	writeNetworkSeed(w, seed)
+	return json.NewEncoder(w).Encode(&ResetResult{NetworkSeed: hex.EncodeToString(seed)})
+}
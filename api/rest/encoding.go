@@ -119,6 +119,48 @@ func DecodeUnsequencedTransactions(in []*EncodedUnsequencedTransaction) ([]*api.
 	return out, nil
 }
 
+func EncodeReceipts(in []*api.Receipt) []*EncodedReceipt {
+	out := make([]*EncodedReceipt, 0, len(in))
+	for _, r := range in {
+		out = append(out, &EncodedReceipt{
+			Index:     r.Index,
+			Hash:      hex.EncodeToString(r.Hash),
+			StateHash: hex.EncodeToString(r.StateHash),
+			Timestamp: r.Timestamp,
+			Duplicate: r.Duplicate,
+			Signature: hex.EncodeToString(r.Signature),
+		})
+	}
+	return out
+}
+
+func DecodeReceipts(in []*EncodedReceipt) ([]*api.Receipt, error) {
+	out := make([]*api.Receipt, 0, len(in))
+	for i, r := range in {
+		hash, err := hex.DecodeString(r.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode hash for receipt %d: %v", i, err)
+		}
+		stateHash, err := hex.DecodeString(r.StateHash)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode state hash for receipt %d: %v", i, err)
+		}
+		signature, err := hex.DecodeString(r.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode signature for receipt %d: %v", i, err)
+		}
+		out = append(out, &api.Receipt{
+			Index:     r.Index,
+			Hash:      hash,
+			StateHash: stateHash,
+			Timestamp: r.Timestamp,
+			Duplicate: r.Duplicate,
+			Signature: signature,
+		})
+	}
+	return out, nil
+}
+
 func EncodeServerStatus(in *api.ServerStatusResult) *ServerStatusResult {
 	return &ServerStatusResult{
 		NetworkType: in.NetworkType,
@@ -127,6 +169,11 @@ func EncodeServerStatus(in *api.ServerStatusResult) *ServerStatusResult {
 		ServerTime:  in.ServerTime,
 		Ready:       in.Ready,
 		Version:     Version,
+		MerkleRoot:  hex.EncodeToString(in.MerkleRoot),
+		StateHash:   hex.EncodeToString(in.StateHash),
+		PublicKey:   hex.EncodeToString(in.PublicKey),
+		Signature:   hex.EncodeToString(in.Signature),
+		SeedHistory: EncodeSeedHistory(in.SeedHistory),
 	}
 }
 
@@ -135,11 +182,150 @@ func DecodeServerStatus(in *ServerStatusResult) (*api.ServerStatusResult, error)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to decode network seed: %v", err)
 	}
+	root, err := hex.DecodeString(in.MerkleRoot)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode Merkle root: %v", err)
+	}
+	stateHash, err := hex.DecodeString(in.StateHash)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode state hash: %v", err)
+	}
+	publicKey, err := hex.DecodeString(in.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode public key: %v", err)
+	}
+	signature, err := hex.DecodeString(in.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode signature: %v", err)
+	}
+	history, err := DecodeSeedHistory(in.SeedHistory)
+	if err != nil {
+		return nil, err
+	}
 	return &api.ServerStatusResult{
 		NetworkType: in.NetworkType,
 		NetworkSeed: seed,
 		LastIndex:   in.LastIndex,
 		ServerTime:  in.ServerTime,
 		Ready:       in.Ready,
+		MerkleRoot:  root,
+		StateHash:   stateHash,
+		PublicKey:   publicKey,
+		Signature:   signature,
+		SeedHistory: history,
+	}, nil
+}
+
+func EncodeSeedHistory(in []*api.SeedReset) []*EncodedSeedReset {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]*EncodedSeedReset, 0, len(in))
+	for _, r := range in {
+		out = append(out, &EncodedSeedReset{
+			NetworkSeed: hex.EncodeToString(r.NetworkSeed),
+			LastIndex:   r.LastIndex,
+			ResetTime:   r.ResetTime,
+		})
+	}
+	return out
+}
+
+func DecodeSeedHistory(in []*EncodedSeedReset) ([]*api.SeedReset, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	out := make([]*api.SeedReset, 0, len(in))
+	for i, r := range in {
+		seed, err := hex.DecodeString(r.NetworkSeed)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode network seed for reset %d: %v", i, err)
+		}
+		out = append(out, &api.SeedReset{
+			NetworkSeed: seed,
+			LastIndex:   r.LastIndex,
+			ResetTime:   r.ResetTime,
+		})
+	}
+	return out, nil
+}
+
+func EncodeInclusionProof(in *api.GetInclusionProofResult) *InclusionProofResult {
+	path := make([]string, 0, len(in.AuditPath))
+	for _, h := range in.AuditPath {
+		path = append(path, hex.EncodeToString(h))
+	}
+	return &InclusionProofResult{
+		Index:     in.Index,
+		TreeSize:  in.TreeSize,
+		Hash:      hex.EncodeToString(in.Hash),
+		Root:      hex.EncodeToString(in.Root),
+		AuditPath: path,
+	}
+}
+
+func DecodeInclusionProof(in *InclusionProofResult) (*api.GetInclusionProofResult, error) {
+	hash, err := hex.DecodeString(in.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode hash: %v", err)
+	}
+	root, err := hex.DecodeString(in.Root)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode root: %v", err)
+	}
+	path := make([][]byte, 0, len(in.AuditPath))
+	for i, h := range in.AuditPath {
+		node, err := hex.DecodeString(h)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode audit path hash %d: %v", i, err)
+		}
+		path = append(path, node)
+	}
+	return &api.GetInclusionProofResult{
+		Index:     in.Index,
+		TreeSize:  in.TreeSize,
+		Hash:      hash,
+		Root:      root,
+		AuditPath: path,
+	}, nil
+}
+
+func EncodeConsistencyProof(in *api.GetConsistencyProofResult) *ConsistencyProofResult {
+	proof := make([]string, 0, len(in.Proof))
+	for _, h := range in.Proof {
+		proof = append(proof, hex.EncodeToString(h))
+	}
+	return &ConsistencyProofResult{
+		From:     in.From,
+		To:       in.To,
+		FromRoot: hex.EncodeToString(in.FromRoot),
+		ToRoot:   hex.EncodeToString(in.ToRoot),
+		Proof:    proof,
+	}
+}
+
+func DecodeConsistencyProof(in *ConsistencyProofResult) (*api.GetConsistencyProofResult, error) {
+	fromRoot, err := hex.DecodeString(in.FromRoot)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode old root: %v", err)
+	}
+	toRoot, err := hex.DecodeString(in.ToRoot)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode new root: %v", err)
+	}
+	proof := make([][]byte, 0, len(in.Proof))
+	for i, h := range in.Proof {
+		node, err := hex.DecodeString(h)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode proof hash %d: %v", i, err)
+		}
+		proof = append(proof, node)
+	}
+	return &api.GetConsistencyProofResult{
+		From:     in.From,
+		To:       in.To,
+		FromRoot: fromRoot,
+		ToRoot:   toRoot,
+		Proof:    proof,
 	}, nil
 }
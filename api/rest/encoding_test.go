@@ -186,7 +186,14 @@ func TestHashedUnsequencedTransactionEncodeDecode(t *testing.T) {
 func TestServerStatusEncodeDecode(t *testing.T) {
 	now := time.Now().UnixNano()
 	seed := utils.RandomData(1, 100)
-	in := &api.ServerStatusResult{seed[0], "test", 1234, now, true}
+	in := &api.ServerStatusResult{
+		NetworkSeed: seed[0],
+		NetworkType: "test",
+		LastIndex:   1234,
+		ServerTime:  now,
+		Ready:       true,
+		SeedHistory: []*api.SeedReset{{NetworkSeed: seed[0], LastIndex: 12, ResetTime: now}},
+	}
 
 	encoded := rest.EncodeServerStatus(in)
 	decoded, err := rest.DecodeServerStatus(encoded)
@@ -197,4 +204,5 @@ func TestServerStatusEncodeDecode(t *testing.T) {
 	st.Expect(t, decoded.ServerTime, now)
 	st.Expect(t, decoded.LastIndex, int64(1234))
 	st.Expect(t, decoded.Ready, true)
+	st.Expect(t, decoded.SeedHistory, in.SeedHistory)
 }
@@ -0,0 +1,105 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
+
+// ExportContentType is the media type of exports, holding one JSON encoded
+// transaction per line.
+const ExportContentType = "application/x-ndjson"
+
+// exportHandler streams the transactions in the requested range as
+// newline-delimited JSON. Transactions are read from the ledger in batches and
+// written out as they go, so memory use doesn't grow with the size of the
+// export. The last index of the export is set in the Symbiont-Last-Index
+// header. Errors happening once the response has started are sent as a last
+// line holding only an error.
+func (s *Server) exportHandler(w http.ResponseWriter, r *http.Request) error {
+	// Parse export parameters.
+	r.ParseForm()
+	p := struct {
+		From int64 `schema:"from"`
+		To   int64 `schema:"to"`
+	}{1, 0}
+	err := schemaDecoder.Decode(&p, r.Form)
+	if err != nil {
+		return &handleError{err, "Failed to decode form", http.StatusBadRequest}
+	}
+	seed, err := hex.DecodeString(r.Header.Get(SymbiontNetworkSeedHeader))
+	if err != nil {
+		return &handleError{err, "Failed to parse network seed", http.StatusBadRequest}
+	}
+
+	// Check the range and seed against the ledger before starting the
+	// response, so that errors can be reported with a status code.
+	status, err := s.ledger.ServerStatus(r.Context(), nil)
+	if err != nil {
+		return err
+	}
+	if len(seed) > 0 && !bytes.Equal(seed, status.NetworkSeed) {
+		writeNetworkSeed(w, status.NetworkSeed)
+		return &handleError{api.NetworkSeedMismatchError(status.NetworkSeed),
+			"Network seed mismatch", http.StatusPreconditionFailed}
+	}
+	to := p.To
+	if to == 0 {
+		to = status.LastIndex
+	}
+	if to > status.LastIndex {
+		return &handleError{api.NotFoundError("Requested index is beyond the last index"),
+			"Transaction not found", http.StatusNotFound}
+	}
+	if p.From < 1 || p.From > to+1 {
+		return &handleError{api.BadRequestError("Requested range is out of order"),
+			"Bad request", http.StatusBadRequest}
+	}
+
+	w.Header().Set("Content-Type", ExportContentType)
+	writeNetworkSeed(w, status.NetworkSeed)
+	w.Header().Set(SymbiontLastIndexHeader, strconv.FormatInt(to, 10))
+	w.WriteHeader(http.StatusOK)
+
+	s.infof("Exporting transactions [%d, %d]", p.From, to)
+	enc := json.NewEncoder(w)
+	for index := p.From; index <= to; {
+		count := to - index + 1
+		if count > s.options.maxCount {
+			count = s.options.maxCount
+		}
+		res, err := s.ledger.ReadTransactions(r.Context(), &api.ReadRequest{
+			NetworkSeed: status.NetworkSeed,
+			Index:       index,
+			Count:       count,
+		})
+		if err == nil && len(res.Transactions) == 0 {
+			err = fmt.Errorf("No transactions read at index %d", index)
+		}
+		if err != nil {
+			s.warnf("Export failed at index %d: %v", index, err)
+			enc.Encode(&TransactionResult{Error: err.Error()})
+			return nil
+		}
+		for _, tx := range EncodeSequencedTransactions(res.Transactions) {
+			if tx.Index > to {
+				break
+			}
+			if err := enc.Encode(&TransactionResult{EncodedSequencedTransaction: tx}); err != nil {
+				// The client has most likely gone away.
+				s.warnf("Export failed at index %d: %v", tx.Index, err)
+				return nil
+			}
+			index = tx.Index + 1
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// acceptsGzip returns true if the request lists gzip as acceptable in its
+// Accept-Encoding header, ie. with a quality value that isn't 0 (RFC 7231).
+func acceptsGzip(r *http.Request) bool {
+	for _, a := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		// Encodings take parameters just like media types.
+		e, params, err := mime.ParseMediaType(a)
+		if err != nil || e != "gzip" {
+			continue
+		}
+		q, ok := params["q"]
+		if !ok {
+			return true
+		}
+		if v, err := strconv.ParseFloat(q, 64); err == nil && v > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter compresses the body of a response with gzip.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w gzipResponseWriter) Write(b []byte) (int, error) {
+	return w.gz.Write(b)
+}
+
+// Flush sends any data buffered for compression, so that streams aren't held
+// up.
+func (w gzipResponseWriter) Flush() {
+	w.gz.Flush()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// decodeBody returns a reader of the request body, decompressing it if it's
+// gzip encoded.
+func decodeBody(r *http.Request) (io.ReadCloser, error) {
+	switch e := r.Header.Get("Content-Encoding"); e {
+	case "", "identity":
+		return r.Body, nil
+	case "gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, &handleError{err, "Failed to decompress body", http.StatusBadRequest}
+		}
+		return gz, nil
+	default:
+		return nil, &handleError{fmt.Errorf("unsupported encoding %q", e),
+			"Failed to decode body", http.StatusUnsupportedMediaType}
+	}
+}
@@ -19,6 +19,9 @@ type options struct {
 	defaultPollTimeout time.Duration
 	logger             Logger
 	contextWithTimeout timeoutContextFactory
+	signedTypes        []string
+	admin              bool
+	adminToken         string
 }
 
 var defaultOptions = options{
@@ -62,3 +65,32 @@ func WithTimeoutContextFactory(f timeoutContextFactory) Option {
 		o.contextWithTimeout = f
 	}
 }
+
+// WithSignedTypes makes the server reject appended transactions with types
+// matching the filters, as used by api.MatchesType, unless their data is an
+// envelope validly signed by its author (see the api/envelope package).
+func WithSignedTypes(filters ...string) Option {
+	return func(o *options) {
+		o.signedTypes = filters
+	}
+}
+
+// WithAdmin enables the administrative routes under /admin, such as importing
+// a history into an empty ledger. They shouldn't be exposed to untrusted
+// clients.
+func WithAdmin() Option {
+	return func(o *options) {
+		o.admin = true
+	}
+}
+
+// WithAdminToken enables the administrative routes, like WithAdmin, but only
+// for requests with an `Authorization: Bearer <token>` header holding the
+// token. Destructive routes, such as resetting the ledger, are only enabled
+// with a token.
+func WithAdminToken(token string) Option {
+	return func(o *options) {
+		o.admin = true
+		o.adminToken = token
+	}
+}
@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
+
+// ProtobufContentType is the media type of request and response bodies holding
+// the protobuf encoded messages of the ledger API, as an alternative to JSON.
+// Binary fields are sent unencoded, which roughly halves the size of reads of
+// binary data. Errors are always sent in JSON.
+const ProtobufContentType = "application/x-protobuf"
+
+// IsProtobuf returns true if the Content-Type of the headers is
+// ProtobufContentType.
+func IsProtobuf(h http.Header) bool {
+	t, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	return err == nil && t == ProtobufContentType
+}
+
+// acceptsProtobuf returns true if the request lists ProtobufContentType as
+// acceptable in its Accept header.
+func acceptsProtobuf(r *http.Request) bool {
+	for _, a := range strings.Split(r.Header.Get("Accept"), ",") {
+		t, params, err := mime.ParseMediaType(a)
+		if err == nil && t == ProtobufContentType && params["q"] != "0" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeProtobuf writes the message as a protobuf encoded response.
+func writeProtobuf(w http.ResponseWriter, msg proto.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("Failed to encode response: %v", err)
+	}
+	w.Header().Set("Content-Type", ProtobufContentType)
+	_, err = w.Write(data)
+	return err
+}
+
+// DecodeProtobuf reads a protobuf encoded message from the body.
+func DecodeProtobuf(body io.Reader, msg proto.Message) error {
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("Failed to read body: %v", err)
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("Failed to unmarshal %T: %v", msg, err)
+	}
+	return nil
+}
+
+// verifyHash checks the hash of a transaction against its type and data.
+func verifyHash(typ string, data, hash []byte) bool {
+	h := sha256.Sum256(append([]byte(typ), data...))
+	return bytes.Equal(hash, h[:])
+}
+
+// EncodeProtobufAppendRequest encodes the transactions of an append request in
+// protobuf, calculating the hashes of those that have none set.
+func EncodeProtobufAppendRequest(in *api.AppendRequest) ([]byte, error) {
+	for _, tx := range in.Transactions {
+		if len(tx.Hash) == 0 {
+			hash := sha256.Sum256(append([]byte(tx.Type), tx.Data...))
+			tx.Hash = hash[:]
+		}
+	}
+	data, err := proto.Marshal(&api.AppendRequest{Transactions: in.Transactions})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to encode request: %v", err)
+	}
+	return data, nil
+}
+
+// DecodeProtobufAppendRequest decodes a protobuf encoded append request,
+// verifying the hashes of its transactions. As with JSON, the network seed and
+// expected last index are taken from the header and parameters only.
+func DecodeProtobufAppendRequest(body io.Reader) (api.AppendRequest, error) {
+	var msg api.AppendRequest
+	if err := DecodeProtobuf(body, &msg); err != nil {
+		return api.AppendRequest{}, fmt.Errorf("Failed to decode request: %v", err)
+	}
+	for i, tx := range msg.Transactions {
+		if !verifyHash(tx.Type, tx.Data, tx.Hash) {
+			return api.AppendRequest{}, fmt.Errorf("Hash mismatch on transaction %d", i)
+		}
+	}
+	return api.AppendRequest{Transactions: msg.Transactions}, nil
+}
+
+// DecodeProtobufReadResult decodes a protobuf encoded read result, verifying
+// the hashes of its transactions.
+func DecodeProtobufReadResult(body io.Reader) (*api.ReadResult, error) {
+	var msg api.ReadResult
+	if err := DecodeProtobuf(body, &msg); err != nil {
+		return nil, err
+	}
+	for i, tx := range msg.Transactions {
+		if !verifyHash(tx.Type, tx.Data, tx.Hash) {
+			return nil, fmt.Errorf("Hash mismatch on transaction %d", i)
+		}
+	}
+	return &msg, nil
+}
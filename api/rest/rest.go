@@ -1,18 +1,23 @@
 // Package rest provides a REST API for the ledger. Data is exchanged encoded
-// in JSON format.
+// in JSON format, or as protobuf messages for clients asking for it on reads,
+// appends and status requests.
 package rest
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
+	"golang.org/x/net/context"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/api/envelope"
 )
 
 // URLPrefix is the prefix used for writing and reading transactions.
@@ -23,6 +28,15 @@ const URLPrefix = "/transactions"
 // request will be rejected. Responses will have the server's seed set on them.
 const SymbiontNetworkSeedHeader = "Symbiont-Network-Seed"
 
+// SymbiontLastIndexHeader is the name of the header holding the ledger's last
+// index on responses to conditional appends that were rejected because of a
+// last index mismatch.
+const SymbiontLastIndexHeader = "Symbiont-Last-Index"
+
+// asyncAppendTimeout bounds the time the ledger is given to handle an
+// asynchronous append, which outlives the request it came in.
+const asyncAppendTimeout = time.Minute
+
 // schemaDecoder is a HTTP parameter decoder from gorilla/schema.
 var schemaDecoder = schema.NewDecoder()
 
@@ -46,9 +60,21 @@ func NewServer(ledger api.LedgerServer, opt ...Option) *Server {
 
 	r := mux.NewRouter()
 	r.Methods("GET").Path(URLPrefix + "/{index:[0-9]+}").Handler(s.handler(s.readHandler))
+	r.Methods("GET").Path(URLPrefix + "/{index:[0-9]+}/stream").Handler(s.handler(s.streamHandler))
+	r.Methods("GET").Path(URLPrefix + "/{index:[0-9]+}/proof").Handler(s.handler(s.proofHandler))
+	r.Methods("GET").Path(URLPrefix + "/by-hash/{hash:[0-9a-fA-F]+}").Handler(s.handler(s.hashHandler))
+	r.Methods("GET").Path("/proofs/consistency").Handler(s.handler(s.consistencyHandler))
+	r.Methods("GET").Path("/export").Handler(s.handler(s.exportHandler))
 	// Allow optional trailing slash on append requests.
 	r.Methods("POST").Path(URLPrefix + `{_slash:\/?}`).Handler(s.handler(s.appendHandler))
 	r.Methods("GET").Path("/").Handler(s.handler(s.statusHandler))
+	if s.options.admin {
+		r.Methods("POST").Path("/admin/import").Handler(s.handler(s.authorize(s.importHandler)))
+		r.Methods("GET", "PUT").Path("/admin/faults").Handler(s.handler(s.authorize(s.faultsHandler)))
+	}
+	if s.options.adminToken != "" {
+		r.Methods("POST").Path("/admin/reset").Handler(s.handler(s.authorize(s.resetHandler)))
+	}
 	s.router = r
 	return s
 }
@@ -68,13 +94,21 @@ func (e handleError) Error() string {
 	return fmt.Sprintf("%s: %v", e.msg, e.err)
 }
 
-// handler wraps a request handler with common logging and checks.
+// handler wraps a request handler with common logging and checks, and
+// compresses responses for clients accepting gzip.
 func (s *Server) handler(fn func(http.ResponseWriter, *http.Request) error) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		s.infof("Handling request: %s %q", r.Method, r.URL.Path)
 
 		w.Header().Add("Content-Type", "application/json")
-		w.Header().Add("Symbiont-Ledger-Version", Version)
+		w.Header().Add(VersionHeader, Version)
+		w.Header().Add("Vary", "Accept-Encoding")
+		if acceptsGzip(r) {
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			defer gz.Close()
+			w = gzipResponseWriter{w, gz}
+		}
 
 		err := fn(w, r)
 		if err != nil {
@@ -104,7 +138,8 @@ func (s *Server) readHandler(w http.ResponseWriter, r *http.Request) error {
 		MaxCount     int64         `schema:"max_count"`
 		MetadataOnly bool          `schema:"metadata_only"`
 		Timeout      time.Duration `schema:"timeout"`
-	}{s.options.defaultCount, false, s.options.defaultPollTimeout}
+		Types        []string      `schema:"type"`
+	}{s.options.defaultCount, false, s.options.defaultPollTimeout, nil}
 	err := schemaDecoder.Decode(&p, r.Form)
 	if err != nil {
 		return &handleError{err, "Failed to decode form", http.StatusBadRequest}
@@ -127,7 +162,12 @@ func (s *Server) readHandler(w http.ResponseWriter, r *http.Request) error {
 	ctx, cancel := s.options.contextWithTimeout(r.Context(), p.Timeout)
 	defer cancel()
 
-	res, err := s.ledger.ReadTransactions(ctx, &api.ReadRequest{seed, index, p.MaxCount})
+	res, err := s.ledger.ReadTransactions(ctx, &api.ReadRequest{
+		NetworkSeed: seed,
+		Index:       index,
+		Count:       p.MaxCount,
+		Types:       p.Types,
+	})
 	if err != nil {
 		switch err := err.(type) {
 		case api.BadRequestError:
@@ -142,8 +182,12 @@ func (s *Server) readHandler(w http.ResponseWriter, r *http.Request) error {
 		}
 	}
 
-	// Format read response.
+	// Format read response. When filtering on types the ledger may have
+	// scanned past the last transaction returned.
 	limit := index + int64(len(res.Transactions))
+	if res.LastIndex >= limit {
+		limit = res.LastIndex + 1
+	}
 	out := ReadResult{
 		FirstIndex: index,
 		LastIndex:  limit - 1,
@@ -153,14 +197,249 @@ func (s *Server) readHandler(w http.ResponseWriter, r *http.Request) error {
 	}
 	s.infof("Returning %d transactions, indexes [%d, %d)", len(res.Transactions), index, limit)
 	w.Header().Add(SymbiontNetworkSeedHeader, hex.EncodeToString(res.NetworkSeed))
+	if acceptsProtobuf(r) {
+		// The first index is implied by the request.
+		pb := &api.ReadResult{NetworkSeed: res.NetworkSeed, LastIndex: out.LastIndex}
+		if !p.MetadataOnly {
+			pb.Transactions = res.Transactions
+		}
+		return writeProtobuf(w, pb)
+	}
 	return json.NewEncoder(w).Encode(&out)
 }
 
+// LastEventIDHeader is the header used by Server-Sent Events clients to resume
+// a stream after the event with the given id.
+const LastEventIDHeader = "Last-Event-ID"
+
+// streamHandler streams transactions as Server-Sent Events, starting at the
+// requested index, or after the one in the Last-Event-ID header if set. The
+// ledger is long-polled for new transactions until the client disconnects.
+// If the client didn't provide a network seed, the one returned by the first
+// read is used for the rest of the stream, so that a reset of the ledger is
+// reported rather than silently restarting at a different log. Errors, such as
+// a network seed mismatch, are sent as an `error` event after which the stream
+// is closed.
+func (s *Server) streamHandler(w http.ResponseWriter, r *http.Request) error {
+	// Parse stream parameters. The network seed can also be provided as a
+	// parameter since browsers' EventSource can't set headers.
+	r.ParseForm()
+	p := struct {
+		NetworkSeed string `schema:"network_seed"`
+	}{r.Header.Get(SymbiontNetworkSeedHeader)}
+	err := schemaDecoder.Decode(&p, r.Form)
+	if err != nil {
+		return &handleError{err, "Failed to decode form", http.StatusBadRequest}
+	}
+	seed, err := hex.DecodeString(p.NetworkSeed)
+	if err != nil {
+		return &handleError{err, "Failed to parse network seed", http.StatusBadRequest}
+	}
+	index, err := strconv.ParseInt(mux.Vars(r)["index"], 10, 64)
+	if err != nil {
+		return &handleError{err, "Failed to parse index", http.StatusBadRequest}
+	}
+	if id := r.Header.Get(LastEventIDHeader); id != "" {
+		last, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return &handleError{err, "Failed to parse " + LastEventIDHeader, http.StatusBadRequest}
+		}
+		index = last + 1
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return fmt.Errorf("Streaming not supported")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	s.infof("Streaming transactions from index %d", index)
+	for {
+		ctx, cancel := s.options.contextWithTimeout(r.Context(), s.options.defaultPollTimeout)
+		res, err := s.ledger.ReadTransactions(ctx, &api.ReadRequest{
+			NetworkSeed: seed,
+			Index:       index,
+			Count:       s.options.defaultCount,
+		})
+		cancel()
+		if r.Context().Err() != nil {
+			s.infof("Stream closed by client at index %d", index)
+			return nil
+		}
+		if err == nil && len(seed) > 0 && !bytes.Equal(res.NetworkSeed, seed) {
+			err = api.NetworkSeedMismatchError(res.NetworkSeed)
+		}
+		if err != nil {
+			s.warnf("Stream failed at index %d: %v", index, err)
+			writeStreamError(w, err)
+			return nil
+		}
+		if len(seed) == 0 {
+			seed = res.NetworkSeed
+		}
+
+		if len(res.Transactions) == 0 {
+			// Keep the connection alive through proxies while waiting.
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		for _, tx := range EncodeSequencedTransactions(res.Transactions) {
+			data, err := json.Marshal(tx)
+			if err != nil {
+				writeStreamError(w, err)
+				return nil
+			}
+			fmt.Fprintf(w, "id: %d\ndata: %s\n\n", tx.Index, data)
+			index = tx.Index + 1
+		}
+		flusher.Flush()
+	}
+}
+
+// writeStreamError sends an error event on a Server-Sent Events stream.
+func writeStreamError(w http.ResponseWriter, err error) {
+	e := StreamError{Error: err.Error()}
+	if err, ok := err.(api.NetworkSeedMismatchError); ok {
+		e.NetworkSeed = hex.EncodeToString(err.CorrectSeed())
+	}
+	data, _ := json.Marshal(&e)
+	fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+}
+
+// hashHandler looks up a transaction by its hash and returns it.
+func (s *Server) hashHandler(w http.ResponseWriter, r *http.Request) error {
+	seed, err := hex.DecodeString(r.Header.Get(SymbiontNetworkSeedHeader))
+	if err != nil {
+		return &handleError{err, "Failed to parse network seed", http.StatusBadRequest}
+	}
+	hash, err := hex.DecodeString(mux.Vars(r)["hash"])
+	if err != nil {
+		return &handleError{err, "Failed to parse hash", http.StatusBadRequest}
+	}
+
+	res, err := s.ledger.GetTransaction(r.Context(), &api.GetTransactionRequest{
+		NetworkSeed: seed,
+		Hash:        hash,
+	})
+	if err != nil {
+		switch err := err.(type) {
+		case api.BadRequestError:
+			return &handleError{err, "Bad request", http.StatusBadRequest}
+		case api.NotFoundError:
+			return &handleError{err, "Transaction not found", http.StatusNotFound}
+		case api.NetworkSeedMismatchError:
+			w.Header().Add(SymbiontNetworkSeedHeader, hex.EncodeToString(err.CorrectSeed()))
+			return &handleError{err, "Network seed mismatch", http.StatusPreconditionFailed}
+		default:
+			return err
+		}
+	}
+
+	s.infof("Returning transaction %d", res.Transaction.Index)
+	w.Header().Add(SymbiontNetworkSeedHeader, hex.EncodeToString(res.NetworkSeed))
+	return json.NewEncoder(w).Encode(&TransactionResult{
+		EncodedSequencedTransaction: EncodeSequencedTransactions(
+			[]*api.SequencedTransaction{res.Transaction})[0],
+	})
+}
+
+// proofHandler forwards requests for inclusion proofs to the ledger API.
+func (s *Server) proofHandler(w http.ResponseWriter, r *http.Request) error {
+	r.ParseForm()
+	p := struct {
+		TreeSize int64 `schema:"tree_size"`
+	}{}
+	err := schemaDecoder.Decode(&p, r.Form)
+	if err != nil {
+		return &handleError{err, "Failed to decode form", http.StatusBadRequest}
+	}
+	seed, err := hex.DecodeString(r.Header.Get(SymbiontNetworkSeedHeader))
+	if err != nil {
+		return &handleError{err, "Failed to parse network seed", http.StatusBadRequest}
+	}
+	index, err := strconv.ParseInt(mux.Vars(r)["index"], 10, 64)
+	if err != nil {
+		return &handleError{err, "Failed to parse index", http.StatusBadRequest}
+	}
+
+	res, err := s.ledger.GetInclusionProof(r.Context(), &api.GetInclusionProofRequest{
+		NetworkSeed: seed,
+		Index:       index,
+		TreeSize:    p.TreeSize,
+	})
+	if err != nil {
+		switch err := err.(type) {
+		case api.BadRequestError:
+			return &handleError{err, "Bad request", http.StatusBadRequest}
+		case api.NotFoundError:
+			return &handleError{err, "Tree size not found", http.StatusNotFound}
+		case api.NetworkSeedMismatchError:
+			w.Header().Add(SymbiontNetworkSeedHeader, hex.EncodeToString(err.CorrectSeed()))
+			return &handleError{err, "Network seed mismatch", http.StatusPreconditionFailed}
+		default:
+			return err
+		}
+	}
+
+	s.infof("Returning inclusion proof of transaction %d in tree size %d", res.Index, res.TreeSize)
+	w.Header().Add(SymbiontNetworkSeedHeader, hex.EncodeToString(res.NetworkSeed))
+	return json.NewEncoder(w).Encode(EncodeInclusionProof(res))
+}
+
+// consistencyHandler forwards requests for consistency proofs to the ledger
+// API.
+func (s *Server) consistencyHandler(w http.ResponseWriter, r *http.Request) error {
+	r.ParseForm()
+	p := struct {
+		From *int64 `schema:"from"`
+		To   int64  `schema:"to"`
+	}{}
+	err := schemaDecoder.Decode(&p, r.Form)
+	if err != nil {
+		return &handleError{err, "Failed to decode form", http.StatusBadRequest}
+	}
+	if p.From == nil {
+		return &handleError{fmt.Errorf("missing from parameter"), "Failed to decode form", http.StatusBadRequest}
+	}
+	seed, err := hex.DecodeString(r.Header.Get(SymbiontNetworkSeedHeader))
+	if err != nil {
+		return &handleError{err, "Failed to parse network seed", http.StatusBadRequest}
+	}
+
+	res, err := s.ledger.GetConsistencyProof(r.Context(), &api.GetConsistencyProofRequest{
+		NetworkSeed: seed,
+		From:        *p.From,
+		To:          p.To,
+	})
+	if err != nil {
+		switch err := err.(type) {
+		case api.BadRequestError:
+			return &handleError{err, "Bad request", http.StatusBadRequest}
+		case api.NotFoundError:
+			return &handleError{err, "Tree size not found", http.StatusNotFound}
+		case api.NetworkSeedMismatchError:
+			w.Header().Add(SymbiontNetworkSeedHeader, hex.EncodeToString(err.CorrectSeed()))
+			return &handleError{err, "Network seed mismatch", http.StatusPreconditionFailed}
+		default:
+			return err
+		}
+	}
+
+	s.infof("Returning consistency proof from tree size %d to %d", res.From, res.To)
+	w.Header().Add(SymbiontNetworkSeedHeader, hex.EncodeToString(res.NetworkSeed))
+	return json.NewEncoder(w).Encode(EncodeConsistencyProof(res))
+}
+
 // appendHandler parses append requests and forwards them to the ledger API.
 func (s *Server) appendHandler(w http.ResponseWriter, r *http.Request) error {
 	// Parse request and parameters.
 	r.ParseForm()
-	p := struct{ Async bool }{}
+	p := struct {
+		Async             bool
+		ExpectedLastIndex *int64 `schema:"expected_last_index"`
+	}{}
 	err := schemaDecoder.Decode(&p, r.Form)
 	if err != nil {
 		return &handleError{err, "Failed to decode form", http.StatusBadRequest}
@@ -170,17 +449,55 @@ func (s *Server) appendHandler(w http.ResponseWriter, r *http.Request) error {
 		return &handleError{err, "Failed to parse network seed", http.StatusBadRequest}
 	}
 
-	req, err := DecodeAppendRequest(r.Body)
+	body, err := decodeBody(r)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	var req api.AppendRequest
+	if IsProtobuf(r.Header) {
+		req, err = DecodeProtobufAppendRequest(body)
+	} else {
+		req, err = DecodeAppendRequest(body)
+	}
 	if err != nil {
 		return &handleError{err, "Failed to parse body", http.StatusBadRequest}
 	}
 	req.NetworkSeed = seed
+	if len(s.options.signedTypes) > 0 {
+		for i, tx := range req.Transactions {
+			if !api.MatchesType(tx.Type, s.options.signedTypes) {
+				continue
+			}
+			if _, err := envelope.Open(tx.Type, tx.Data); err != nil {
+				return &handleError{fmt.Errorf("transaction %d: %v", i, err),
+					"Refused to append transactions", http.StatusBadRequest}
+			}
+		}
+	}
+	if p.ExpectedLastIndex != nil {
+		req.ExpectedLastIndex = &api.ExpectedIndex{Index: *p.ExpectedLastIndex}
+	}
 
 	// Perform append request.
 	s.debugf("Appending %d transactions", len(req.Transactions))
 	if p.Async {
-		// In the asynchronous case we don't wait for the append to complete.
-		go s.ledger.AppendTransactions(r.Context(), &req) // ignore returned values.
+		// In the asynchronous case we don't wait for the append to complete,
+		// so there'd be no way to report a last index mismatch.
+		if req.ExpectedLastIndex != nil {
+			return &handleError{fmt.Errorf("async append with expected last index"),
+				"Refused to append transactions", http.StatusBadRequest}
+		}
+		// The request's context is canceled as soon as we return, so the
+		// append gets a context of its own.
+		go func() {
+			ctx, cancel := context.WithTimeout(api.WithAsync(context.Background()), asyncAppendTimeout)
+			defer cancel()
+			s.ledger.AppendTransactions(ctx, &req) // ignore returned values.
+		}()
+		if acceptsProtobuf(r) {
+			return writeProtobuf(w, &api.AppendResult{})
+		}
 		return json.NewEncoder(w).Encode(&AppendResult{Status: appendStatusPending})
 	}
 	res, err := s.ledger.AppendTransactions(r.Context(), &req)
@@ -188,6 +505,9 @@ func (s *Server) appendHandler(w http.ResponseWriter, r *http.Request) error {
 		switch err := err.(type) {
 		case api.BadRequestError:
 			return &handleError{err, "Refused to append transactions", http.StatusBadRequest}
+		case api.ConflictError:
+			w.Header().Add(SymbiontLastIndexHeader, strconv.FormatInt(err.LastIndex(), 10))
+			return &handleError{err, "Last index mismatch", http.StatusConflict}
 		case api.NetworkSeedMismatchError:
 			w.Header().Add(SymbiontNetworkSeedHeader, hex.EncodeToString(err.CorrectSeed()))
 			return &handleError{err, "Network seed mismatch", http.StatusPreconditionFailed}
@@ -199,9 +519,13 @@ func (s *Server) appendHandler(w http.ResponseWriter, r *http.Request) error {
 	// Format append response.
 	s.infof("Transactions appended with indexes ending at %d", res.LastIndex)
 	w.Header().Add(SymbiontNetworkSeedHeader, hex.EncodeToString(res.NetworkSeed))
+	if acceptsProtobuf(r) {
+		return writeProtobuf(w, res)
+	}
 	return json.NewEncoder(w).Encode(&AppendResult{
 		LastIndex: res.LastIndex,
 		Status:    appendStatusSequenced,
+		Receipts:  EncodeReceipts(res.Receipts),
 	})
 }
 
@@ -213,6 +537,9 @@ func (s *Server) statusHandler(w http.ResponseWriter, r *http.Request) error {
 		return err
 	}
 	writeNetworkSeed(w, status.NetworkSeed)
+	if acceptsProtobuf(r) {
+		return writeProtobuf(w, status)
+	}
 	return json.NewEncoder(w).Encode(EncodeServerStatus(status))
 }
 
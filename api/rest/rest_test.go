@@ -1,8 +1,15 @@
 package rest_test
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/crypto/ed25519"
 	"golang.org/x/net/context"
 	"io/ioutil"
 	"net/http"
@@ -12,6 +19,9 @@ import (
 
 	"github.com/symbiont-io/assembly-sdk/api"
 	"github.com/symbiont-io/assembly-sdk/api/rest"
+	"github.com/symbiont-io/assembly-sdk/client/envelope"
+	"github.com/symbiont-io/assembly-sdk/mock"
+	"github.com/symbiont-io/assembly-sdk/mock/faults"
 
 	"github.com/nbio/st"
 	"github.com/symbiont-io/assembly-sdk/test/utils"
@@ -29,10 +39,13 @@ func (l *dummyLedger) ReadTransactions(ctx context.Context, req *api.ReadRequest
 	l.lastDeadline, _ = ctx.Deadline()
 
 	if req.Index < 100 {
-		return &api.ReadResult{[]byte("seed"), []*api.SequencedTransaction{
-			utils.MockSequencedTransaction(req.Index),
-			utils.MockSequencedTransaction(req.Index + 1),
-		}}, nil
+		return &api.ReadResult{
+			NetworkSeed: []byte("seed"),
+			Transactions: []*api.SequencedTransaction{
+				utils.MockSequencedTransaction(req.Index),
+				utils.MockSequencedTransaction(req.Index + 1),
+			},
+		}, nil
 	} else {
 		return nil, api.NotFoundError("Requested index is too far in the future")
 	}
@@ -41,7 +54,7 @@ func (l *dummyLedger) ReadTransactions(ctx context.Context, req *api.ReadRequest
 func (l *dummyLedger) AppendTransactions(_ context.Context, req *api.AppendRequest) (*api.AppendResult, error) {
 	serverSeed := []byte("right seed")
 	if bytes.Equal(req.NetworkSeed, serverSeed) {
-		return &api.AppendResult{serverSeed, int64(234)}, nil
+		return &api.AppendResult{NetworkSeed: serverSeed, LastIndex: 234}, nil
 	} else {
 		return nil, api.NetworkSeedMismatchError(serverSeed)
 	}
@@ -51,6 +64,22 @@ func (l *dummyLedger) ServerStatus(_ context.Context, _ *api.Empty) (*api.Server
 	return nil, nil
 }
 
+func (l *dummyLedger) SubscribeTransactions(_ *api.SubscribeRequest, _ api.Ledger_SubscribeTransactionsServer) error {
+	return nil
+}
+
+func (l *dummyLedger) GetTransaction(_ context.Context, _ *api.GetTransactionRequest) (*api.GetTransactionResult, error) {
+	return nil, api.NotFoundError("Transaction not found")
+}
+
+func (l *dummyLedger) GetInclusionProof(_ context.Context, _ *api.GetInclusionProofRequest) (*api.GetInclusionProofResult, error) {
+	return nil, api.NotFoundError("Tree size is beyond the last index")
+}
+
+func (l *dummyLedger) GetConsistencyProof(_ context.Context, _ *api.GetConsistencyProofRequest) (*api.GetConsistencyProofResult, error) {
+	return nil, api.NotFoundError("Tree size is beyond the last index")
+}
+
 func TestServerBadPaths(t *testing.T) {
 	m := dummyLedger{}
 	ts := httptest.NewServer(rest.NewServer(&m).Router())
@@ -138,6 +167,8 @@ func TestServerReadParams(t *testing.T) {
 	p := url.Values{}
 	p.Add("max_count", "42")
 	p.Add("timeout", "12345")
+	p.Add("type", "symbiont/example")
+	p.Add("type", "symbiont/test/*")
 	u.RawQuery = p.Encode()
 
 	before := time.Now()
@@ -152,6 +183,7 @@ func TestServerReadParams(t *testing.T) {
 
 	st.Expect(t, m.lastReq.Index, int64(1))
 	st.Expect(t, m.lastReq.Count, int64(42))
+	st.Expect(t, m.lastReq.Types, []string{"symbiont/example", "symbiont/test/*"})
 	st.Expect(t, m.lastDeadline.After(after.Add(time.Duration(12345))), false)
 	st.Expect(t, m.lastDeadline.Before(before.Add(time.Duration(12345))), false)
 }
@@ -245,6 +277,409 @@ func TestServerAppendBadSeed(t *testing.T) {
 	st.Expect(t, resp.Header.Get(rest.SymbiontNetworkSeedHeader), hex.EncodeToString([]byte("right seed")))
 }
 
+func TestServerAppendConflict(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l).Router())
+	defer ts.Close()
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+
+	post := func(query string) *http.Response {
+		data := bytes.NewBufferString(`{"transactions":[{"data":"QQ==","hash":"559aead08264d5795d3909718cdd05abd49572e84fe55590eef31a88a08fdffd"}]}`)
+		resp, err := http.Post(ts.URL+rest.URLPrefix+query, "application/json", data)
+		st.Assert(t, err, nil)
+		resp.Body.Close()
+		return resp
+	}
+	resp := post("?expected_last_index=2")
+	st.Expect(t, resp.StatusCode, http.StatusConflict)
+	st.Expect(t, resp.Header.Get(rest.SymbiontLastIndexHeader), "3")
+
+	resp = post("?expected_last_index=3&async=true")
+	st.Expect(t, resp.StatusCode, http.StatusBadRequest)
+
+	resp = post("?expected_last_index=3")
+	st.Expect(t, resp.StatusCode, http.StatusOK)
+}
+
+func TestServerAppendSignedTypes(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l, rest.WithSignedTypes("orders/*")).Router())
+	defer ts.Close()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	st.Assert(t, err, nil)
+	signed, err := envelope.Seal(key, "orders/new", []byte("payload"))
+	st.Assert(t, err, nil)
+
+	post := func(txs ...*api.UnsequencedTransaction) *http.Response {
+		data, err := rest.EncodeAppendRequest(&api.AppendRequest{Transactions: txs})
+		st.Assert(t, err, nil)
+		resp, err := http.Post(ts.URL+rest.URLPrefix, "application/json", bytes.NewReader(data))
+		st.Assert(t, err, nil)
+		resp.Body.Close()
+		return resp
+	}
+	resp := post(signed, &api.UnsequencedTransaction{Type: "chat", Data: []byte("hi")})
+	st.Expect(t, resp.StatusCode, http.StatusOK)
+
+	resp = post(&api.UnsequencedTransaction{Type: "orders/new", Data: []byte("payload")})
+	st.Expect(t, resp.StatusCode, http.StatusBadRequest)
+
+	resp = post(&api.UnsequencedTransaction{Type: "orders/cancel", Data: signed.Data})
+	st.Expect(t, resp.StatusCode, http.StatusBadRequest)
+
+	status, err := l.ServerStatus(context.Background(), &api.Empty{})
+	st.Assert(t, err, nil)
+	st.Expect(t, status.LastIndex, int64(2))
+}
+
+func TestServerProtobuf(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l).Router())
+	defer ts.Close()
+
+	do := func(method, path string, body []byte) *http.Response {
+		req, err := http.NewRequest(method, ts.URL+path, bytes.NewReader(body))
+		st.Assert(t, err, nil)
+		req.Header.Set("Content-Type", rest.ProtobufContentType)
+		req.Header.Set("Accept", rest.ProtobufContentType)
+		resp, err := http.DefaultClient.Do(req)
+		st.Assert(t, err, nil)
+		return resp
+	}
+
+	txs := utils.RandomUnsequencedTransactions(2, 100)
+	body, err := rest.EncodeProtobufAppendRequest(&api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	resp := do("POST", rest.URLPrefix, body)
+	defer resp.Body.Close()
+	st.Assert(t, resp.StatusCode, http.StatusOK)
+	st.Expect(t, rest.IsProtobuf(resp.Header), true)
+	var appended api.AppendResult
+	st.Assert(t, rest.DecodeProtobuf(resp.Body, &appended), nil)
+	st.Expect(t, appended.LastIndex, int64(2))
+	st.Expect(t, len(appended.Receipts), 2)
+
+	resp = do("GET", rest.URLPrefix+"/1", nil)
+	defer resp.Body.Close()
+	st.Assert(t, resp.StatusCode, http.StatusOK)
+	read, err := rest.DecodeProtobufReadResult(resp.Body)
+	st.Assert(t, err, nil)
+	st.Expect(t, read.LastIndex, int64(2))
+	st.Assert(t, len(read.Transactions), 2)
+	st.Expect(t, read.Transactions[0].Data, txs[0].Data)
+
+	resp = do("GET", "/", nil)
+	defer resp.Body.Close()
+	var status api.ServerStatusResult
+	st.Assert(t, rest.DecodeProtobuf(resp.Body, &status), nil)
+	st.Expect(t, status.LastIndex, int64(2))
+
+	// Hashes are verified, and errors are sent in JSON.
+	txs[0].Hash = txs[1].Hash
+	body, err = rest.EncodeProtobufAppendRequest(&api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	resp = do("POST", rest.URLPrefix, body)
+	defer resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusBadRequest)
+	st.Expect(t, resp.Header.Get("Content-Type"), "application/json")
+}
+
+func TestServerGzip(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l).Router())
+	defer ts.Close()
+
+	post := func(encoding string, body []byte) *http.Response {
+		req, err := http.NewRequest("POST", ts.URL+rest.URLPrefix, bytes.NewReader(body))
+		st.Assert(t, err, nil)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Encoding", encoding)
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := http.DefaultClient.Do(req)
+		st.Assert(t, err, nil)
+		return resp
+	}
+
+	data, err := rest.EncodeAppendRequest(&api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(2, 100),
+	})
+	st.Assert(t, err, nil)
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	gz.Write(data)
+	gz.Close()
+	resp := post("gzip", b.Bytes())
+	defer resp.Body.Close()
+	st.Assert(t, resp.StatusCode, http.StatusOK)
+	st.Expect(t, resp.Header.Get("Content-Encoding"), "gzip")
+	r, err := gzip.NewReader(resp.Body)
+	st.Assert(t, err, nil)
+	var res rest.AppendResult
+	st.Assert(t, json.NewDecoder(r).Decode(&res), nil)
+	st.Expect(t, res.LastIndex, int64(2))
+
+	resp = post("br", data)
+	defer resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusUnsupportedMediaType)
+
+	// Corrupt bodies are rejected.
+	resp = post("gzip", data)
+	defer resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusBadRequest)
+}
+
+func TestServerGzipQuality(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l).Router())
+	defer ts.Close()
+
+	for _, c := range []struct {
+		accept string
+		gzip   bool
+	}{
+		{"gzip", true},
+		{"gzip;q=0.5", true},
+		{"identity, gzip;q=1.0", true},
+		{"gzip;q=0", false},
+		{"gzip;q=0.0", false},
+		{"gzip;q=0.00", false},
+		{"gzip;q=bad", false},
+		{"deflate", false},
+	} {
+		req, err := http.NewRequest("GET", ts.URL+"/", nil)
+		st.Assert(t, err, nil)
+		req.Header.Set("Accept-Encoding", c.accept)
+		resp, err := http.DefaultClient.Do(req)
+		st.Assert(t, err, nil)
+		resp.Body.Close()
+		if got := resp.Header.Get("Content-Encoding") == "gzip"; got != c.gzip {
+			t.Errorf("%s: gzip %v, expected %v", c.accept, got, c.gzip)
+		}
+	}
+}
+
+func TestServerExport(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l, rest.WithMaxCount(2)).Router())
+	defer ts.Close()
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(5, 100),
+	})
+	st.Assert(t, err, nil)
+
+	resp, err := http.Get(ts.URL + "/export?from=2")
+	st.Assert(t, err, nil)
+	defer resp.Body.Close()
+	st.Assert(t, resp.StatusCode, http.StatusOK)
+	st.Expect(t, resp.Header.Get("Content-Type"), rest.ExportContentType)
+	st.Expect(t, resp.Header.Get(rest.SymbiontLastIndexHeader), "5")
+	var indexes []int64
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var tx rest.EncodedSequencedTransaction
+		st.Assert(t, json.Unmarshal(scanner.Bytes(), &tx), nil)
+		indexes = append(indexes, tx.Index)
+	}
+	st.Expect(t, indexes, []int64{2, 3, 4, 5})
+
+	for query, code := range map[string]int{
+		"?from=2&to=3": http.StatusOK,
+		"?from=6":      http.StatusOK,
+		"?from=0":      http.StatusBadRequest,
+		"?from=4&to=2": http.StatusBadRequest,
+		"?to=6":        http.StatusNotFound,
+	} {
+		resp, err := http.Get(ts.URL + "/export" + query)
+		st.Assert(t, err, nil)
+		resp.Body.Close()
+		st.Expect(t, resp.StatusCode, code)
+	}
+}
+
+func TestServerImport(t *testing.T) {
+	src := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(src).Router())
+	defer ts.Close()
+	_, err := src.AppendTransactions(context.Background(), &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(5, 100),
+	})
+	st.Assert(t, err, nil)
+	resp, err := http.Get(ts.URL + "/export")
+	st.Assert(t, err, nil)
+	history, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	st.Assert(t, err, nil)
+	seed := resp.Header.Get(rest.SymbiontNetworkSeedHeader)
+
+	l := mock.NewLedger()
+	ts2 := httptest.NewServer(rest.NewServer(l, rest.WithAdmin()).Router())
+	defer ts2.Close()
+	post := func(body []byte) *http.Response {
+		req, err := http.NewRequest("POST", ts2.URL+"/admin/import", bytes.NewReader(body))
+		st.Assert(t, err, nil)
+		req.Header.Set("Content-Type", rest.ExportContentType)
+		req.Header.Set(rest.SymbiontNetworkSeedHeader, seed)
+		resp, err := http.DefaultClient.Do(req)
+		st.Assert(t, err, nil)
+		resp.Body.Close()
+		return resp
+	}
+
+	// Histories that don't link are refused.
+	lines := bytes.SplitAfter(history, []byte("\n"))
+	broken := bytes.Join(append(lines[:2:2], lines[3:]...), nil)
+	st.Expect(t, post(broken).StatusCode, http.StatusBadRequest)
+
+	st.Expect(t, post(history).StatusCode, http.StatusOK)
+	want, _ := src.ServerStatus(context.Background(), nil)
+	got, _ := l.ServerStatus(context.Background(), nil)
+	st.Expect(t, got.NetworkSeed, want.NetworkSeed)
+	st.Expect(t, got.LastIndex, int64(5))
+	st.Expect(t, got.StateHash, want.StateHash)
+
+	resp = post(history)
+	st.Expect(t, resp.StatusCode, http.StatusConflict)
+	st.Expect(t, resp.Header.Get(rest.SymbiontLastIndexHeader), "5")
+
+	// Admin routes are disabled by default.
+	resp, err = http.Post(ts.URL+"/admin/import", rest.ExportContentType, bytes.NewReader(history))
+	st.Assert(t, err, nil)
+	resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusNotFound)
+}
+
+func TestServerFaults(t *testing.T) {
+	l := faults.NewLedger(mock.NewLedger())
+	ts := httptest.NewServer(rest.NewServer(l, rest.WithAdmin()).Router())
+	defer ts.Close()
+	put := func(body string) *http.Response {
+		req, err := http.NewRequest("PUT", ts.URL+"/admin/faults", strings.NewReader(body))
+		st.Assert(t, err, nil)
+		resp, err := http.DefaultClient.Do(req)
+		st.Assert(t, err, nil)
+		resp.Body.Close()
+		return resp
+	}
+
+	st.Expect(t, put(`{"server_error_rate":1.5}`).StatusCode, http.StatusBadRequest)
+	st.Expect(t, put(`{"latency":{"distribution":"pareto"}}`).StatusCode, http.StatusBadRequest)
+	st.Expect(t, put(`{"not_found_rate":1,"not_ready":true}`).StatusCode, http.StatusOK)
+	resp, err := http.Get(ts.URL + "/admin/faults")
+	st.Assert(t, err, nil)
+	var c api.FaultConfig
+	err = json.NewDecoder(resp.Body).Decode(&c)
+	resp.Body.Close()
+	st.Assert(t, err, nil)
+	st.Expect(t, c, api.FaultConfig{NotFoundRate: 1, NotReady: true})
+
+	resp, err = http.Get(ts.URL + rest.URLPrefix + "/1?timeout=0")
+	st.Assert(t, err, nil)
+	resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusNotFound)
+	resp, err = http.Get(ts.URL + "/")
+	st.Assert(t, err, nil)
+	var status rest.ServerStatusResult
+	err = json.NewDecoder(resp.Body).Decode(&status)
+	resp.Body.Close()
+	st.Assert(t, err, nil)
+	st.Expect(t, status.Ready, false)
+
+	// Dropped async appends are still accepted.
+	st.Expect(t, put(`{"async_drop_rate":1}`).StatusCode, http.StatusOK)
+	body, _ := json.Marshal(&rest.AppendRequest{
+		Transactions: rest.EncodeUnsequencedTransactions(utils.RandomUnsequencedTransactions(1, 100)),
+	})
+	resp, err = http.Post(ts.URL+rest.URLPrefix+"?async=true", "application/json", bytes.NewReader(body))
+	st.Assert(t, err, nil)
+	resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusOK)
+
+	// Ledgers not injecting faults can't be configured.
+	ts2 := httptest.NewServer(rest.NewServer(mock.NewLedger(), rest.WithAdmin()).Router())
+	defer ts2.Close()
+	resp, err = http.Get(ts2.URL + "/admin/faults")
+	st.Assert(t, err, nil)
+	resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusNotImplemented)
+
+	// Wrapping a ledger doesn't hide that it can't be imported into or reset.
+	ts3 := httptest.NewServer(rest.NewServer(faults.NewLedger(&dummyLedger{}),
+		rest.WithAdminToken("secret")).Router())
+	defer ts3.Close()
+	for i, path := range []string{"/admin/import", "/admin/reset"} {
+		req, err := http.NewRequest("POST", ts3.URL+path, nil)
+		st.Assert(t, err, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err = http.DefaultClient.Do(req)
+		st.Assert(t, err, nil)
+		resp.Body.Close()
+		st.Expect(t, resp.StatusCode, http.StatusNotImplemented, i)
+	}
+}
+
+func TestServerReset(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l, rest.WithAdminToken("secret")).Router())
+	defer ts.Close()
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(2, 100),
+	})
+	st.Assert(t, err, nil)
+	old, _ := l.ServerStatus(context.Background(), nil)
+	post := func(auth string) *http.Response {
+		req, err := http.NewRequest("POST", ts.URL+"/admin/reset", nil)
+		st.Assert(t, err, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		st.Assert(t, err, nil)
+		return resp
+	}
+
+	for i, auth := range []string{"", "secret", "Bearer wrong"} {
+		resp := post(auth)
+		resp.Body.Close()
+		st.Expect(t, resp.StatusCode, http.StatusUnauthorized, i)
+	}
+	// Other admin routes need the token too.
+	resp, err := http.Post(ts.URL+"/admin/import", rest.ExportContentType, nil)
+	st.Assert(t, err, nil)
+	resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusUnauthorized)
+
+	resp = post("Bearer secret")
+	var res rest.ResetResult
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	resp.Body.Close()
+	st.Assert(t, err, nil)
+	st.Expect(t, resp.StatusCode, http.StatusOK)
+	st.Expect(t, resp.Header.Get(rest.SymbiontNetworkSeedHeader), res.NetworkSeed)
+
+	resp, err = http.Get(ts.URL + "/")
+	st.Assert(t, err, nil)
+	var status rest.ServerStatusResult
+	err = json.NewDecoder(resp.Body).Decode(&status)
+	resp.Body.Close()
+	st.Assert(t, err, nil)
+	st.Expect(t, status.NetworkSeed, res.NetworkSeed)
+	st.Expect(t, status.LastIndex, int64(0))
+	st.Assert(t, len(status.SeedHistory), 1)
+	st.Expect(t, status.SeedHistory[0].NetworkSeed, hex.EncodeToString(old.NetworkSeed))
+	st.Expect(t, status.SeedHistory[0].LastIndex, int64(2))
+
+	// Resets need a token.
+	ts2 := httptest.NewServer(rest.NewServer(l, rest.WithAdmin()).Router())
+	defer ts2.Close()
+	resp, err = http.Post(ts2.URL+"/admin/reset", "application/json", nil)
+	st.Assert(t, err, nil)
+	resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusNotFound)
+}
+
 func TestServerAppendUnparseableSeed(t *testing.T) {
 	m := dummyLedger{}
 	ts := httptest.NewServer(rest.NewServer(&m).Router())
@@ -316,6 +751,22 @@ func (l *badDummyLedger) ServerStatus(_ context.Context, _ *api.Empty) (*api.Ser
 	return nil, nil
 }
 
+func (l *badDummyLedger) SubscribeTransactions(_ *api.SubscribeRequest, _ api.Ledger_SubscribeTransactionsServer) error {
+	return nil
+}
+
+func (l *badDummyLedger) GetTransaction(_ context.Context, _ *api.GetTransactionRequest) (*api.GetTransactionResult, error) {
+	return nil, api.NotFoundError("Transaction not found")
+}
+
+func (l *badDummyLedger) GetInclusionProof(_ context.Context, _ *api.GetInclusionProofRequest) (*api.GetInclusionProofResult, error) {
+	return nil, api.NotFoundError("Tree size is beyond the last index")
+}
+
+func (l *badDummyLedger) GetConsistencyProof(_ context.Context, _ *api.GetConsistencyProofRequest) (*api.GetConsistencyProofResult, error) {
+	return nil, api.NotFoundError("Tree size is beyond the last index")
+}
+
 func TestServerAsyncWrite(t *testing.T) {
 	m := badDummyLedger{}
 	ts := httptest.NewServer(rest.NewServer(&m).Router())
@@ -344,3 +795,203 @@ func TestServerAsyncWrite(t *testing.T) {
 	msg, _ := ioutil.ReadAll(resp.Body)
 	st.Expect(t, strings.TrimSpace(string(msg)), `{"status":"pending"}`)
 }
+
+// readEvent reads a Server-Sent Event from r, skipping comments, and returns
+// its fields.
+func readEvent(t *testing.T, r *bufio.Reader) map[string]string {
+	event := map[string]string{}
+	for {
+		line, err := r.ReadString('\n')
+		st.Assert(t, err, nil)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if len(event) > 0 {
+				return event
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		kv := strings.SplitN(line, ": ", 2)
+		st.Assert(t, len(kv), 2)
+		event[kv[0]] = kv[1]
+	}
+}
+
+// openStream opens an event stream from the provided index, and returns a
+// reader for it along with a function closing it.
+func openStream(t *testing.T, ts *httptest.Server, index string, header http.Header) (*bufio.Reader, func()) {
+	req, _ := http.NewRequest("GET", ts.URL+rest.URLPrefix+"/"+index+"/stream", nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	st.Assert(t, err, nil)
+	st.Assert(t, resp.StatusCode, http.StatusOK)
+	st.Expect(t, resp.Header.Get("Content-Type"), "text/event-stream")
+	return bufio.NewReader(resp.Body), func() { resp.Body.Close() }
+}
+
+func TestServerStream(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l, rest.WithDefaultPollTimeout(50*time.Millisecond)).Router())
+	defer ts.Close()
+
+	txs := utils.RandomUnsequencedTransactions(3, 100)
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{Transactions: txs[:2]})
+	st.Assert(t, err, nil)
+
+	r, stop := openStream(t, ts, "1", nil)
+	defer stop()
+	for i := int64(1); i <= 2; i++ {
+		event := readEvent(t, r)
+		st.Expect(t, event["id"], fmt.Sprint(i))
+		var tx rest.EncodedSequencedTransaction
+		st.Assert(t, json.Unmarshal([]byte(event["data"]), &tx), nil)
+		st.Expect(t, tx.Index, i)
+	}
+
+	// New transactions are sent as they are appended.
+	_, err = l.AppendTransactions(context.Background(), &api.AppendRequest{Transactions: txs[2:]})
+	st.Assert(t, err, nil)
+	st.Expect(t, readEvent(t, r)["id"], "3")
+}
+
+func TestServerStreamResume(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l).Router())
+	defer ts.Close()
+
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+
+	header := http.Header{}
+	header.Set(rest.LastEventIDHeader, "2")
+	r, stop := openStream(t, ts, "1", header)
+	defer stop()
+	st.Expect(t, readEvent(t, r)["id"], "3")
+}
+
+func TestServerStreamBadSeed(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l).Router())
+	defer ts.Close()
+
+	status, _ := l.ServerStatus(context.Background(), nil)
+	header := http.Header{}
+	header.Set(rest.SymbiontNetworkSeedHeader, hex.EncodeToString([]byte("bad seed")))
+	r, stop := openStream(t, ts, "1", header)
+	defer stop()
+
+	event := readEvent(t, r)
+	st.Expect(t, event["event"], "error")
+	var e rest.StreamError
+	st.Assert(t, json.Unmarshal([]byte(event["data"]), &e), nil)
+	st.Expect(t, e.NetworkSeed, hex.EncodeToString(status.NetworkSeed))
+}
+
+func TestServerStreamReset(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l, rest.WithDefaultPollTimeout(50*time.Millisecond)).Router())
+	defer ts.Close()
+
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(2, 100),
+	})
+	st.Assert(t, err, nil)
+
+	// The stream sticks to the seed it started on, even without one given.
+	r, stop := openStream(t, ts, "1", nil)
+	defer stop()
+	st.Expect(t, readEvent(t, r)["id"], "1")
+	st.Expect(t, readEvent(t, r)["id"], "2")
+	seed, err := l.Reset()
+	st.Assert(t, err, nil)
+
+	event := readEvent(t, r)
+	st.Expect(t, event["event"], "error")
+	var e rest.StreamError
+	st.Assert(t, json.Unmarshal([]byte(event["data"]), &e), nil)
+	st.Expect(t, e.NetworkSeed, hex.EncodeToString(seed))
+}
+
+func TestServerGetTransaction(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l).Router())
+	defer ts.Close()
+
+	txs := utils.RandomUnsequencedTransactions(3, 100)
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+
+	hash := sha256.Sum256(append([]byte(txs[1].Type), txs[1].Data...))
+	resp, err := http.Get(ts.URL + rest.URLPrefix + "/by-hash/" + hex.EncodeToString(hash[:]))
+	st.Assert(t, err, nil)
+	defer resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusOK)
+	var res rest.TransactionResult
+	st.Assert(t, json.NewDecoder(resp.Body).Decode(&res), nil)
+	st.Expect(t, res.Index, int64(2))
+	st.Expect(t, res.Hash, hex.EncodeToString(hash[:]))
+
+	// Unknown hash
+	resp, err = http.Get(ts.URL + rest.URLPrefix + "/by-hash/00ff")
+	st.Assert(t, err, nil)
+	defer resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusNotFound)
+}
+
+func TestServerInclusionProof(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l).Router())
+	defer ts.Close()
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+
+	resp, err := http.Get(ts.URL + rest.URLPrefix + "/2/proof?tree_size=3")
+	st.Assert(t, err, nil)
+	defer resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusOK)
+	var res rest.InclusionProofResult
+	st.Assert(t, json.NewDecoder(resp.Body).Decode(&res), nil)
+	st.Expect(t, res.Index, int64(2))
+	st.Expect(t, res.TreeSize, int64(3))
+	st.Expect(t, len(res.AuditPath), 2)
+
+	resp, err = http.Get(ts.URL + rest.URLPrefix + "/2/proof?tree_size=4")
+	st.Assert(t, err, nil)
+	resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusNotFound)
+}
+
+func TestServerConsistencyProof(t *testing.T) {
+	l := mock.NewLedger()
+	ts := httptest.NewServer(rest.NewServer(l).Router())
+	defer ts.Close()
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(5, 100),
+	})
+	st.Assert(t, err, nil)
+
+	resp, err := http.Get(ts.URL + "/proofs/consistency?from=3")
+	st.Assert(t, err, nil)
+	defer resp.Body.Close()
+	st.Expect(t, resp.StatusCode, http.StatusOK)
+	var res rest.ConsistencyProofResult
+	st.Assert(t, json.NewDecoder(resp.Body).Decode(&res), nil)
+	st.Expect(t, res.From, int64(3))
+	st.Expect(t, res.To, int64(5))
+	st.Expect(t, len(res.Proof), 4)
+
+	for _, query := range []string{"", "?from=3&to=2"} {
+		resp, err = http.Get(ts.URL + "/proofs/consistency" + query)
+		st.Assert(t, err, nil)
+		resp.Body.Close()
+		st.Expect(t, resp.StatusCode, http.StatusBadRequest)
+	}
+}
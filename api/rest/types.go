@@ -6,15 +6,16 @@ package rest
 
 // ReadResult is the result of a read from the ledger.
 type ReadResult struct {
-	// FirstIndex is the index of the first transaction returned, or the index
-	// used in the request if no transactions are returned.
+	// FirstIndex is the index used in the request. Unless filtering on types,
+	// this is the index of the first transaction returned, if any.
 	FirstIndex int64 `json:"first_index"`
 
 	// Transactions is an array of transactions read from the ledger.
 	Transactions []*EncodedSequencedTransaction `json:"transactions"`
 
-	// LastIndex is the index of the last transaction returned, or one less
-	// than the index in the request if no transactions are returned.
+	// LastIndex is the index of the last transaction scanned, or one less
+	// than the index in the request if none was. Unless filtering on types,
+	// this is the index of the last transaction returned.
 	LastIndex int64 `json:"last_index"`
 
 	// Error is set if an error happened while executing the request.
@@ -55,6 +56,113 @@ type EncodedSequencedTransaction struct {
 	StateHash string `json:"state_hash"`
 }
 
+//
+// Hash lookup route (GET "/transactions/by-hash/:hash")
+//
+
+// TransactionResult is the result of looking up a single transaction.
+type TransactionResult struct {
+	*EncodedSequencedTransaction
+
+	// Error is set if an error happened while executing the request.
+	Error string `json:"error,omitempty"`
+}
+
+//
+// Stream route (GET "/transactions/:index/stream")
+//
+
+// StreamError is the data of the `error` event that ends a stream of
+// transactions.
+type StreamError struct {
+	// Error describes the error that occured.
+	Error string `json:"error"`
+
+	// NetworkSeed is set to the ledger's network seed if the stream was
+	// closed due to a network seed mismatch.
+	NetworkSeed string `json:"network_seed,omitempty"`
+}
+
+//
+// Inclusion proof route (GET "/transactions/:index/proof")
+//
+
+// InclusionProofResult is the audit path proving that a transaction is
+// included in the Merkle tree over the transactions of the ledger.
+type InclusionProofResult struct {
+	// Index is the index of the transaction.
+	Index int64 `json:"tx_index"`
+
+	// TreeSize is the size of the tree the proof is for.
+	TreeSize int64 `json:"tree_size"`
+
+	// Hash is the hex-encoded hash of the transaction.
+	Hash string `json:"hash"`
+
+	// Root is the hex-encoded root hash of the tree.
+	Root string `json:"root"`
+
+	// AuditPath is the list of hex-encoded node hashes needed to calculate the
+	// root hash from the hash of the transaction, ordered from the leaf up.
+	AuditPath []string `json:"audit_path"`
+
+	// Error is set if an error happened while executing the request.
+	Error string `json:"error,omitempty"`
+}
+
+//
+// Consistency proof route (GET "/proofs/consistency")
+//
+
+// ConsistencyProofResult is the proof that the Merkle tree of the ledger has
+// only been appended to between two sizes.
+type ConsistencyProofResult struct {
+	// From is the size of the older tree.
+	From int64 `json:"from"`
+
+	// To is the size of the newer tree.
+	To int64 `json:"to"`
+
+	// FromRoot is the hex-encoded root hash of the older tree.
+	FromRoot string `json:"from_root"`
+
+	// ToRoot is the hex-encoded root hash of the newer tree.
+	ToRoot string `json:"to_root"`
+
+	// Proof is the list of hex-encoded node hashes needed to calculate both
+	// root hashes.
+	Proof []string `json:"proof"`
+
+	// Error is set if an error happened while executing the request.
+	Error string `json:"error,omitempty"`
+}
+
+//
+// Import route (POST "/admin/import")
+//
+
+// ImportResult is the result of importing a history into an empty ledger.
+type ImportResult struct {
+	// LastIndex is the index of the last transaction imported.
+	LastIndex int64 `json:"last_index"`
+
+	// Error is set if an error happened while executing the request.
+	Error string `json:"error,omitempty"`
+}
+
+//
+// Reset route (POST "/admin/reset")
+//
+
+// ResetResult is the result of resetting the ledger.
+type ResetResult struct {
+	// NetworkSeed is the new network seed of the ledger, hex encoded.
+	NetworkSeed string `json:"network_seed"`
+
+	// Error is set if an error happened while executing the request.
+	Error string `json:"error,omitempty"`
+}
+
 //
 // Append route (POST "/transactions/")
 //
@@ -77,10 +185,43 @@ type AppendResult struct {
 	// requests and `pending` if `async=true` is set.
 	Status string `json:"status"`
 
+	// Receipts tell where each transaction in the request was written, in the
+	// order of the request. Absent for `async=true` requests or if the ledger
+	// doesn't provide them.
+	Receipts []*EncodedReceipt `json:"receipts,omitempty"`
+
 	// Error indicates that an error occured while executing the request.
 	Error string `json:"error,omitempty"`
 }
 
+// EncodedReceipt is the encoded representation of the receipt for a
+// transaction written to the ledger.
+type EncodedReceipt struct {
+	// Index is the index assigned to the transaction when writing it to the
+	// ledger.
+	Index int64 `json:"tx_index"`
+
+	// Hash is the hex-encoded SHA256 hash of the transaction type and data.
+	Hash string `json:"hash"`
+
+	// StateHash is the hex-encoded state hash of the ledger after the
+	// transaction.
+	StateHash string `json:"state_hash"`
+
+	// Timestamp is the time the ledger wrote the transaction, in nanoseconds
+	// since the Unix epoch.
+	Timestamp int64 `json:"timestamp"`
+
+	// Duplicate is set if the transaction wasn't written because it's a
+	// duplicate of one already on the ledger, which the receipt refers to.
+	Duplicate bool `json:"duplicate,omitempty"`
+
+	// Signature is the hex-encoded Ed25519 signature of the ledger node over
+	// the network seed and the rest of the receipt. Absent if the node doesn't
+	// sign receipts.
+	Signature string `json:"signature,omitempty"`
+}
+
 const (
 	// appendStatusPending is the AppendResult.Status value for requests that
 	// still haven't been sequenced.
@@ -138,9 +279,43 @@ type ServerStatusResult struct {
 	// some other issue.
 	Ready bool `json:"ready"`
 
+	// MerkleRoot is the hex-encoded root hash of the Merkle tree over the
+	// transactions up to LastIndex. Absent if the ledger doesn't maintain one.
+	MerkleRoot string `json:"merkle_root,omitempty"`
+
+	// StateHash is the hex-encoded state hash of the ledger after the
+	// transaction at LastIndex.
+	StateHash string `json:"state_hash,omitempty"`
+
+	// PublicKey is the hex-encoded Ed25519 public key the ledger node signs
+	// statuses and receipts with. Absent if the node doesn't sign them.
+	PublicKey string `json:"public_key,omitempty"`
+
+	// Signature is the hex-encoded Ed25519 signature of the node over the
+	// network seed, last index, state hash, Merkle root and server time.
+	Signature string `json:"signature,omitempty"`
+
+	// SeedHistory lists the earlier network seeds of the ledger, oldest
+	// first. Absent if the ledger has never been reset.
+	SeedHistory []*EncodedSeedReset `json:"seed_history,omitempty"`
+
 	// Version indicates the version of the ledger API.
 	Version string `json:"version"`
 
 	// Error is set if an error happened while executing the request.
 	Error string `json:"error,omitempty"`
 }
+
+// EncodedSeedReset is the encoded representation of a reset of the ledger,
+// which wiped its transactions and replaced its network seed.
+type EncodedSeedReset struct {
+	// NetworkSeed is the hex-encoded seed the ledger had before the reset.
+	NetworkSeed string `json:"network_seed"`
+
+	// LastIndex is the index of the last transaction on the ledger when it
+	// was reset.
+	LastIndex int64 `json:"last_index"`
+
+	// ResetTime is the time of the reset, in nanoseconds since unix-epoch.
+	ResetTime int64 `json:"reset_time"`
+}
@@ -1,3 +1,7 @@
 package rest
 
-const Version = "1.0.0"
+const Version = "1.2.0"
+
+// VersionHeader is the name of the header holding the version of the API on
+// every response.
+const VersionHeader = "Symbiont-Ledger-Version"
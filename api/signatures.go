@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"golang.org/x/crypto/ed25519"
+)
+
+// Prefixes of the data signed by ledger nodes, so that a signature over one
+// kind of message can't be passed off as another.
+const (
+	statusSignaturePrefix  = "symbiont-status-v1"
+	receiptSignaturePrefix = "symbiont-receipt-v1"
+)
+
+// writeSigned appends length-prefixed byte slices and integers to the data
+// being signed, so that field boundaries are unambiguous.
+func writeSigned(b *bytes.Buffer, fields ...interface{}) {
+	for _, f := range fields {
+		switch f := f.(type) {
+		case []byte:
+			binary.Write(b, binary.BigEndian, uint32(len(f)))
+			b.Write(f)
+		case int64:
+			binary.Write(b, binary.BigEndian, f)
+		case bool:
+			binary.Write(b, binary.BigEndian, f)
+		}
+	}
+}
+
+// statusSignedData returns the data covered by the signature of a status.
+func statusSignedData(s *ServerStatusResult) []byte {
+	b := bytes.NewBufferString(statusSignaturePrefix)
+	writeSigned(b, s.NetworkSeed, s.LastIndex, s.StateHash, s.MerkleRoot, s.ServerTime)
+	return b.Bytes()
+}
+
+// receiptSignedData returns the data covered by the signature of a receipt.
+func receiptSignedData(seed []byte, r *Receipt) []byte {
+	b := bytes.NewBufferString(receiptSignaturePrefix)
+	writeSigned(b, seed, r.Index, r.Hash, r.StateHash, r.Timestamp, r.Duplicate)
+	return b.Bytes()
+}
+
+// SignStatus signs the status head with the private key of a ledger node,
+// setting the public key and signature of the status.
+func SignStatus(key ed25519.PrivateKey, s *ServerStatusResult) {
+	s.PublicKey = key.Public().(ed25519.PublicKey)
+	s.Signature = ed25519.Sign(key, statusSignedData(s))
+}
+
+// VerifyStatus checks that the status was signed by the ledger node with the
+// provided public key. A nil error means the signature is valid.
+func VerifyStatus(key ed25519.PublicKey, s *ServerStatusResult) error {
+	if len(s.Signature) == 0 {
+		return fmt.Errorf("Status is not signed")
+	}
+	if !bytes.Equal(s.PublicKey, key) {
+		return fmt.Errorf("Status signed by untrusted key %x", s.PublicKey)
+	}
+	if !ed25519.Verify(key, statusSignedData(s), s.Signature) {
+		return fmt.Errorf("Invalid status signature")
+	}
+	return nil
+}
+
+// SignReceipt signs the receipt with the private key of a ledger node. The
+// network seed of the ledger is included in the signed data, so that the
+// receipt can't be passed off as one from a different ledger.
+func SignReceipt(key ed25519.PrivateKey, seed []byte, r *Receipt) {
+	r.Signature = ed25519.Sign(key, receiptSignedData(seed, r))
+}
+
+// VerifyReceipt checks that the receipt was signed for the ledger with the
+// provided network seed, by the ledger node with the provided public key. A
+// nil error means the signature is valid.
+func VerifyReceipt(key ed25519.PublicKey, seed []byte, r *Receipt) error {
+	if len(r.Signature) == 0 {
+		return fmt.Errorf("Receipt is not signed")
+	}
+	if !ed25519.Verify(key, receiptSignedData(seed, r), r.Signature) {
+		return fmt.Errorf("Invalid receipt signature")
+	}
+	return nil
+}
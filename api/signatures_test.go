@@ -0,0 +1,50 @@
+package api_test
+
+import (
+	"crypto/rand"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+
+	"github.com/nbio/st"
+	"testing"
+)
+
+func TestSignStatus(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	st.Assert(t, err, nil)
+	s := &api.ServerStatusResult{
+		NetworkSeed: []byte("seed"),
+		LastIndex:   10,
+		StateHash:   []byte("state hash"),
+		MerkleRoot:  []byte("root"),
+		ServerTime:  1234,
+	}
+	api.SignStatus(key, s)
+	st.Expect(t, []byte(s.PublicKey), []byte(pub))
+	st.Expect(t, api.VerifyStatus(pub, s), nil)
+
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	st.Assert(t, err, nil)
+	st.Refute(t, api.VerifyStatus(other, s), nil)
+	s.LastIndex = 11
+	st.Refute(t, api.VerifyStatus(pub, s), nil)
+	s.Signature = nil
+	st.Refute(t, api.VerifyStatus(pub, s), nil)
+}
+
+func TestSignReceipt(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	st.Assert(t, err, nil)
+	r := &api.Receipt{
+		Index:     3,
+		Hash:      []byte("hash"),
+		StateHash: []byte("state hash"),
+		Timestamp: 1234,
+	}
+	api.SignReceipt(key, []byte("seed"), r)
+	st.Expect(t, api.VerifyReceipt(pub, []byte("seed"), r), nil)
+	st.Refute(t, api.VerifyReceipt(pub, []byte("other seed"), r), nil)
+	r.Duplicate = true
+	st.Refute(t, api.VerifyReceipt(pub, []byte("seed"), r), nil)
+}
@@ -0,0 +1,26 @@
+package api
+
+import "strings"
+
+// TypePrefixWildcard is the suffix marking a type filter as a prefix match.
+const TypePrefixWildcard = "*"
+
+// MatchesType returns true if the transaction type matches one of the provided
+// type filters, or if no filters are provided. A filter ending in
+// TypePrefixWildcard matches any type starting with the preceding prefix,
+// while other filters must match exactly.
+func MatchesType(typ string, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, f := range filters {
+		if strings.HasSuffix(f, TypePrefixWildcard) {
+			if strings.HasPrefix(typ, strings.TrimSuffix(f, TypePrefixWildcard)) {
+				return true
+			}
+		} else if typ == f {
+			return true
+		}
+	}
+	return false
+}
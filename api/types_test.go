@@ -0,0 +1,27 @@
+package api_test
+
+import (
+	"github.com/symbiont-io/assembly-sdk/api"
+
+	"github.com/nbio/st"
+	"testing"
+)
+
+func TestMatchesType(t *testing.T) {
+	for _, c := range []struct {
+		typ     string
+		filters []string
+		match   bool
+	}{
+		{"a", nil, true},
+		{"a", []string{"a"}, true},
+		{"a", []string{"b"}, false},
+		{"ab", []string{"a"}, false},
+		{"a/b", []string{"b", "a/*"}, true},
+		{"a", []string{"a*"}, true},
+		{"b/a", []string{"a/*"}, false},
+		{"", []string{"*"}, true},
+	} {
+		st.Expect(t, api.MatchesType(c.typ, c.filters), c.match)
+	}
+}
@@ -0,0 +1,55 @@
+// Package envelope signs transaction payloads with the Ed25519 key of their
+// author, and verifies the signatures of transactions read from the ledger.
+//
+// The envelopes are those of the api/envelope package: a signed payload is
+// wrapped in an api.Envelope, which is marshalled as the data of the
+// transaction. The signature covers the transaction type as well as the
+// payload, so that a signed payload can't be replayed under another type.
+package envelope
+
+import (
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	apienvelope "github.com/symbiont-io/assembly-sdk/api/envelope"
+)
+
+// Seal signs the payload with the author's private key and returns a
+// transaction of the provided type, with the signed envelope as its data.
+func Seal(key ed25519.PrivateKey, typ string, payload []byte) (*api.UnsequencedTransaction, error) {
+	data, err := proto.Marshal(&api.Envelope{
+		PublicKey: key.Public().(ed25519.PublicKey),
+		Signature: ed25519.Sign(key, apienvelope.SignedData(typ, payload)),
+		Payload:   payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal envelope: %v", err)
+	}
+	return &api.UnsequencedTransaction{Type: typ, Data: data}, nil
+}
+
+// Open unmarshals the envelope in the data of a transaction of the provided
+// type and verifies its signature, as api/envelope's Open does. The returned
+// envelope holds the public key of the author along with the payload.
+func Open(typ string, data []byte) (*api.Envelope, error) {
+	return apienvelope.Open(typ, data)
+}
+
+// Verifier returns a function that checks the envelopes of sequenced
+// transactions with types matching the filters, as used by api.MatchesType,
+// and lets other transactions through. If no filters are provided, every
+// transaction must be signed. The function can be passed to
+// scanner.WithVerifier.
+func Verifier(filters ...string) func(*api.SequencedTransaction) error {
+	return func(tx *api.SequencedTransaction) error {
+		if !api.MatchesType(tx.Type, filters) {
+			return nil
+		}
+		if _, err := Open(tx.Type, tx.Data); err != nil {
+			return fmt.Errorf("Failed to verify transaction %d: %v", tx.Index, err)
+		}
+		return nil
+	}
+}
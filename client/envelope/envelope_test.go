@@ -0,0 +1,63 @@
+package envelope_test
+
+import (
+	"crypto/rand"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/client/envelope"
+
+	"github.com/nbio/st"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	st.Assert(t, err, nil)
+
+	tx, err := envelope.Seal(key, "orders/new", []byte("payload"))
+	st.Assert(t, err, nil)
+	st.Expect(t, tx.Type, "orders/new")
+
+	e, err := envelope.Open(tx.Type, tx.Data)
+	st.Assert(t, err, nil)
+	st.Expect(t, e.Payload, []byte("payload"))
+	st.Expect(t, []byte(e.PublicKey), []byte(pub))
+
+	// The signature covers the type.
+	_, err = envelope.Open("orders/cancel", tx.Data)
+	st.Reject(t, err, nil)
+
+	// Tampered data doesn't verify.
+	tampered := append([]byte(nil), tx.Data...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = envelope.Open(tx.Type, tampered)
+	st.Reject(t, err, nil)
+
+	// Nor does data that isn't an envelope.
+	_, err = envelope.Open(tx.Type, []byte("payload"))
+	st.Reject(t, err, nil)
+}
+
+func TestVerifier(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	st.Assert(t, err, nil)
+	signed, err := envelope.Seal(key, "orders/new", []byte("payload"))
+	st.Assert(t, err, nil)
+
+	verify := envelope.Verifier("orders/*")
+	st.Expect(t, verify(&api.SequencedTransaction{
+		Index: 1, Type: signed.Type, Data: signed.Data,
+	}), nil)
+	st.Reject(t, verify(&api.SequencedTransaction{
+		Index: 2, Type: "orders/new", Data: []byte("payload"),
+	}), nil)
+	st.Expect(t, verify(&api.SequencedTransaction{
+		Index: 3, Type: "chat", Data: []byte("payload"),
+	}), nil)
+
+	// Without filters, every transaction must be signed.
+	st.Reject(t, envelope.Verifier()(&api.SequencedTransaction{
+		Index: 3, Type: "chat", Data: []byte("payload"),
+	}), nil)
+}
@@ -41,7 +41,7 @@ func main() {
 		if err != nil {
 			log.Fatalf("Failed to read user input: %v", err)
 		}
-		_, _ = c.AppendTransactions(context.Background(), &api.AppendRequest{nil, []*api.UnsequencedTransaction{
+		_, _ = c.AppendTransactions(context.Background(), &api.AppendRequest{Transactions: []*api.UnsequencedTransaction{
 			&api.UnsequencedTransaction{Type: "example/chat", Data: []byte(*name + ": " + line)},
 		}})
 	}
@@ -0,0 +1,291 @@
+// Package client is a client library for accessing a ledger's gRPC API.
+//
+// It has the same methods as the REST client in client/rest, so either can be
+// used wherever a ledger client is needed, eg. by a scanner.Scanner. The
+// client holds a single connection, which is safe to use concurrently.
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"golang.org/x/net/context"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/api/grpc"
+	"github.com/symbiont-io/assembly-sdk/client/merkle"
+)
+
+// Client is a ledger API client.
+type Client struct {
+	target  string
+	conn    *gogrpc.ClientConn
+	ledger  api.LedgerClient
+	options options
+	// err is the error setting up the connection failed with, if it did,
+	// which every call returns.
+	err error
+}
+
+// New creates a new Client, talking to the ledger gRPC API found at target
+// (eg. "localhost:4001") and with zero or more options changed from their
+// defaults. Like the REST client's New it can't fail: the connection is
+// established in the background, so an unreachable ledger is reported by the
+// first call rather than here.
+func New(target string, opt ...Option) *Client {
+	c := Client{
+		target:  target,
+		options: defaultOptions,
+	}
+	for _, o := range opt {
+		o(&c.options)
+	}
+	conn, err := gogrpc.Dial(target, gogrpc.WithInsecure())
+	if err != nil {
+		c.err = fmt.Errorf("Failed to connect to %q: %v", target, err)
+		return &c
+	}
+	c.conn = conn
+	c.ledger = api.NewLedgerClient(conn)
+	return &c
+}
+
+// Close closes the connection to the ledger.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// ReadTransactions reads transactions from a ledger, starting at the provided
+// index. If there's not yet any transaction at that index, but it's the next
+// one up, the request will be held for up to the provided timeout before
+// returning. If the requested index is further into the future an error is
+// returned. If a network seed is provided, it will be checked against the
+// ledger's, and an error returned in case of a mismatch. If types are
+// requested, only matching transactions are returned and the result's
+// LastIndex tells where to continue reading from.
+//
+// The server waits for new transactions for half the time left until the
+// deadline of the context. If the context has no deadline, one is set so that
+// the server waits for the configured poll timeout.
+func (c *Client) ReadTransactions(ctx context.Context, req *api.ReadRequest) (*api.ReadResult, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 2*c.options.pollTimeout)
+		defer cancel()
+	}
+	r := *req
+	if r.Count <= 0 {
+		r.Count = c.options.maxCount
+	}
+
+	var trailer metadata.MD
+	res, err := c.ledger.ReadTransactions(ctx, &r, gogrpc.Trailer(&trailer))
+	if err != nil {
+		return nil, grpc.DecodeError(err, trailer)
+	}
+
+	// Verify returned seed, indexes and hashes.
+	if len(req.NetworkSeed) > 0 && !bytes.Equal(req.NetworkSeed, res.NetworkSeed) {
+		return nil, api.NetworkSeedMismatchError(res.NetworkSeed)
+	}
+	// Servers that don't support filtering ignore the types and don't report
+	// the scanned range, so the range ends with the last transaction and
+	// mismatching ones are filtered out here.
+	txs := res.Transactions
+	if n := len(txs); len(req.Types) > 0 && n > 0 && res.LastIndex < req.Index {
+		res.LastIndex = txs[n-1].Index
+	}
+	res.Transactions = txs[:0]
+	index := req.Index
+	for i, tx := range txs {
+		if len(req.Types) == 0 && tx.Index != index {
+			return nil, fmt.Errorf("Unexpected index of tx %d (got %d, expected %d)",
+				i, tx.Index, index)
+		}
+		// Filtered transactions must be in order within the scanned range.
+		if len(req.Types) > 0 && (tx.Index < index || tx.Index > res.LastIndex) {
+			return nil, fmt.Errorf("Unexpected index of tx %d (got %d, expected [%d, %d])",
+				i, tx.Index, index, res.LastIndex)
+		}
+		index = tx.Index + 1
+		hash := sha256.Sum256(append([]byte(tx.Type), tx.Data...))
+		if !bytes.Equal(tx.Hash, hash[:]) {
+			return nil, fmt.Errorf("Hash mismatch on transaction %d", i)
+		}
+		if api.MatchesType(tx.Type, req.Types) {
+			res.Transactions = append(res.Transactions, tx)
+		}
+	}
+	if res.LastIndex < index-1 {
+		res.LastIndex = index - 1
+	}
+	return res, nil
+}
+
+// AppendTransactions appends an array of transactions to the ledger. All
+// provided transactions will be ordered after all transactions already in the
+// ledger, but no particular order respective to each other is guaranteed. If a
+// network seed is provided this will be checked against the ledger's and
+// requests with a mismatching network seed will be rejected. Both successful
+// requests and those rejected due to seed mismatch will return the server's
+// network seed. If an expected last index is set and the ledger has moved past
+// it, an api.ConflictError holding the ledger's last index is returned.
+func (c *Client) AppendTransactions(ctx context.Context, req *api.AppendRequest) (*api.AppendResult, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	// Calculate hashes to protect against corruption.
+	for _, tx := range req.Transactions {
+		if len(tx.Hash) == 0 {
+			hash := sha256.Sum256(append([]byte(tx.Type), tx.Data...))
+			tx.Hash = hash[:]
+		}
+	}
+
+	// Set default timeout if none is provided.
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.options.appendTimeout+c.options.callTimeout)
+		defer cancel()
+	}
+
+	var trailer metadata.MD
+	res, err := c.ledger.AppendTransactions(ctx, req, gogrpc.Trailer(&trailer))
+	if err != nil {
+		return nil, grpc.DecodeError(err, trailer)
+	}
+	return res, nil
+}
+
+// ServerStatus return the status of the node the client is connected to.
+func (c *Client) ServerStatus(ctx context.Context, _ *api.Empty) (*api.ServerStatusResult, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	// Set default timeout if none is provided.
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.options.callTimeout)
+		defer cancel()
+	}
+
+	var trailer metadata.MD
+	res, err := c.ledger.ServerStatus(ctx, &api.Empty{}, gogrpc.Trailer(&trailer))
+	if err != nil {
+		return nil, grpc.DecodeError(err, trailer)
+	}
+	return res, nil
+}
+
+// GetTransaction looks up a transaction by its hash. If the same transaction
+// has been appended more than once, the first one sequenced is returned. If
+// there's no transaction with the hash a NotFoundError is returned.
+func (c *Client) GetTransaction(ctx context.Context, req *api.GetTransactionRequest) (*api.GetTransactionResult, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	// Set default timeout if none is provided.
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.options.callTimeout)
+		defer cancel()
+	}
+
+	var trailer metadata.MD
+	res, err := c.ledger.GetTransaction(ctx, req, gogrpc.Trailer(&trailer))
+	if err != nil {
+		return nil, grpc.DecodeError(err, trailer)
+	}
+
+	// Verify returned seed and hash.
+	if len(req.NetworkSeed) > 0 && !bytes.Equal(req.NetworkSeed, res.NetworkSeed) {
+		return nil, api.NetworkSeedMismatchError(res.NetworkSeed)
+	}
+	tx := res.Transaction
+	if tx == nil {
+		return nil, fmt.Errorf("No transaction in response")
+	}
+	hash := sha256.Sum256(append([]byte(tx.Type), tx.Data...))
+	if !bytes.Equal(tx.Hash, hash[:]) || !bytes.Equal(tx.Hash, req.Hash) {
+		return nil, fmt.Errorf("Hash mismatch on transaction")
+	}
+	return res, nil
+}
+
+// GetInclusionProof requests the audit path proving that the transaction at
+// the requested index is included in the ledger's Merkle tree, of the
+// requested size or up to the last index if none is set. The proof is verified
+// against the root hash in the result before it's returned; callers should
+// compare that root with one they trust.
+func (c *Client) GetInclusionProof(ctx context.Context, req *api.GetInclusionProofRequest) (*api.GetInclusionProofResult, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	// Set default timeout if none is provided.
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.options.callTimeout)
+		defer cancel()
+	}
+
+	var trailer metadata.MD
+	res, err := c.ledger.GetInclusionProof(ctx, req, gogrpc.Trailer(&trailer))
+	if err != nil {
+		return nil, grpc.DecodeError(err, trailer)
+	}
+
+	// Verify returned seed and proof.
+	if len(req.NetworkSeed) > 0 && !bytes.Equal(req.NetworkSeed, res.NetworkSeed) {
+		return nil, api.NetworkSeedMismatchError(res.NetworkSeed)
+	}
+	if res.Index != req.Index || (req.TreeSize != 0 && res.TreeSize != req.TreeSize) {
+		return nil, fmt.Errorf("Unexpected proof (got index %d in tree size %d)", res.Index, res.TreeSize)
+	}
+	if err := merkle.VerifyInclusion(res.Hash, res.Index, res.TreeSize, res.AuditPath, res.Root); err != nil {
+		return nil, fmt.Errorf("Invalid inclusion proof: %v", err)
+	}
+	return res, nil
+}
+
+// GetConsistencyProof requests the proof that the ledger's Merkle tree of the
+// requested from size is a prefix of the tree of the requested to size, or up
+// to the last index if none is set. The proof is verified against the root
+// hashes in the result before it's returned; callers should compare the older
+// root with one they recorded earlier.
+func (c *Client) GetConsistencyProof(ctx context.Context, req *api.GetConsistencyProofRequest) (*api.GetConsistencyProofResult, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	// Set default timeout if none is provided.
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.options.callTimeout)
+		defer cancel()
+	}
+
+	var trailer metadata.MD
+	res, err := c.ledger.GetConsistencyProof(ctx, req, gogrpc.Trailer(&trailer))
+	if err != nil {
+		return nil, grpc.DecodeError(err, trailer)
+	}
+
+	// Verify returned seed and proof.
+	if len(req.NetworkSeed) > 0 && !bytes.Equal(req.NetworkSeed, res.NetworkSeed) {
+		return nil, api.NetworkSeedMismatchError(res.NetworkSeed)
+	}
+	if res.From != req.From || (req.To != 0 && res.To != req.To) {
+		return nil, fmt.Errorf("Unexpected proof (got tree sizes %d to %d)", res.From, res.To)
+	}
+	if err := merkle.VerifyConsistency(res.From, res.To, res.FromRoot, res.ToRoot, res.Proof); err != nil {
+		return nil, fmt.Errorf("Invalid consistency proof: %v", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,182 @@
+package client_test
+
+import (
+	"golang.org/x/net/context"
+	"net"
+	"time"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/api/grpc"
+	"github.com/symbiont-io/assembly-sdk/client/grpc"
+	"github.com/symbiont-io/assembly-sdk/client/scanner"
+	"github.com/symbiont-io/assembly-sdk/mock"
+
+	"github.com/nbio/st"
+	"github.com/symbiont-io/assembly-sdk/test/utils"
+	"testing"
+)
+
+// startServer serves a new mock ledger over gRPC on a local port and returns
+// the ledger, its address and a function stopping the server.
+func startServer(t *testing.T) (*mock.Ledger, string, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	st.Assert(t, err, nil)
+	l := mock.NewLedger()
+	s := grpc.NewServer(l)
+	go s.Serve(lis)
+	return l, lis.Addr().String(), s.Stop
+}
+
+func TestClientAppendRead(t *testing.T) {
+	_, addr, stop := startServer(t)
+	defer stop()
+	c := client.New(addr)
+	defer c.Close()
+
+	ctx := context.Background()
+	status, err := c.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+
+	txs := utils.RandomUnsequencedTransactions(3, 100)
+	res, err := c.AppendTransactions(ctx, &api.AppendRequest{
+		NetworkSeed:  status.NetworkSeed,
+		Transactions: txs,
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.LastIndex, int64(3))
+
+	read, err := c.ReadTransactions(ctx, &api.ReadRequest{
+		NetworkSeed: status.NetworkSeed,
+		Index:       1,
+	})
+	st.Assert(t, err, nil)
+	st.Assert(t, len(read.Transactions), 3)
+	for i, tx := range read.Transactions {
+		st.Expect(t, tx.Data, txs[i].Data)
+	}
+}
+
+func TestClientMaxCount(t *testing.T) {
+	_, addr, stop := startServer(t)
+	defer stop()
+	c := client.New(addr, client.WithMaxCount(2))
+	defer c.Close()
+
+	ctx := context.Background()
+	_, err := c.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+	read, err := c.ReadTransactions(ctx, &api.ReadRequest{Index: 1})
+	st.Assert(t, err, nil)
+	st.Expect(t, len(read.Transactions), 2)
+}
+
+func TestClientPollTimeout(t *testing.T) {
+	_, addr, stop := startServer(t)
+	defer stop()
+	c := client.New(addr, client.WithPollTimeout(50*time.Millisecond))
+	defer c.Close()
+
+	before := time.Now()
+	read, err := c.ReadTransactions(context.Background(), &api.ReadRequest{Index: 1})
+	st.Assert(t, err, nil)
+	st.Expect(t, len(read.Transactions), 0)
+	st.Expect(t, time.Since(before) >= 50*time.Millisecond, true)
+}
+
+func TestClientBadSeed(t *testing.T) {
+	l, addr, stop := startServer(t)
+	defer stop()
+	c := client.New(addr)
+	defer c.Close()
+
+	status, _ := l.ServerStatus(context.Background(), nil)
+	_, err := c.AppendTransactions(context.Background(), &api.AppendRequest{
+		NetworkSeed:  []byte("bad seed"),
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	seed, ok := err.(api.NetworkSeedMismatchError)
+	st.Assert(t, ok, true)
+	st.Expect(t, seed.CorrectSeed(), status.NetworkSeed)
+
+	_, err = c.ReadTransactions(context.Background(), &api.ReadRequest{
+		NetworkSeed: []byte("bad seed"),
+		Index:       1,
+	})
+	_, ok = err.(api.NetworkSeedMismatchError)
+	st.Assert(t, ok, true)
+}
+
+func TestClientNotFound(t *testing.T) {
+	_, addr, stop := startServer(t)
+	defer stop()
+	c := client.New(addr)
+	defer c.Close()
+
+	_, err := c.ReadTransactions(context.Background(), &api.ReadRequest{Index: 5})
+	_, ok := err.(api.NotFoundError)
+	st.Assert(t, ok, true)
+}
+
+func TestClientScanner(t *testing.T) {
+	l, addr, stop := startServer(t)
+	defer stop()
+	c := client.New(addr, client.WithPollTimeout(50*time.Millisecond))
+	defer c.Close()
+
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(5, 100),
+	})
+	st.Assert(t, err, nil)
+
+	s := scanner.New(c)
+	txs := s.Scan(1, nil)
+	for i := int64(1); i <= 5; i++ {
+		select {
+		case tx := <-txs:
+			st.Expect(t, tx.Index, i)
+		case <-time.After(time.Second):
+			t.Fatal("Timed out scanning transactions")
+		}
+	}
+}
+
+func TestClientGetTransaction(t *testing.T) {
+	_, addr, stop := startServer(t)
+	defer stop()
+	c := client.New(addr)
+	defer c.Close()
+
+	ctx := context.Background()
+	req := &api.AppendRequest{Transactions: utils.RandomUnsequencedTransactions(3, 100)}
+	_, err := c.AppendTransactions(ctx, req)
+	st.Assert(t, err, nil)
+
+	res, err := c.GetTransaction(ctx, &api.GetTransactionRequest{Hash: req.Transactions[1].Hash})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.Transaction.Index, int64(2))
+
+	_, err = c.GetTransaction(ctx, &api.GetTransactionRequest{Hash: []byte("unknown")})
+	_, ok := err.(api.NotFoundError)
+	st.Assert(t, ok, true)
+}
+
+func TestClientGetInclusionProof(t *testing.T) {
+	_, addr, stop := startServer(t)
+	defer stop()
+	c := client.New(addr)
+	defer c.Close()
+
+	ctx := context.Background()
+	req := &api.AppendRequest{Transactions: utils.RandomUnsequencedTransactions(5, 100)}
+	_, err := c.AppendTransactions(ctx, req)
+	st.Assert(t, err, nil)
+	status, err := c.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+
+	res, err := c.GetInclusionProof(ctx, &api.GetInclusionProofRequest{Index: 2})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.Hash, req.Transactions[1].Hash)
+	st.Expect(t, res.Root, status.MerkleRoot)
+}
@@ -0,0 +1,122 @@
+// Package merkle verifies proofs against the Merkle tree a ledger maintains
+// over the hashes of its transactions.
+//
+// The tree is built as described in RFC 6962 (Certificate Transparency), using
+// the hashing of the api/merkle package, which can also calculate the root
+// hash over transactions read from the ledger. Proofs can be checked offline,
+// against a root hash obtained from a trusted source: inclusion proofs show
+// that a transaction is in the tree, and consistency proofs that a tree of one
+// size is a prefix of a larger one.
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+
+	apimerkle "github.com/symbiont-io/assembly-sdk/api/merkle"
+)
+
+// VerifyInclusion checks that the transaction hash is at the provided index in
+// the tree of size treeSize with the provided root hash, using the audit path
+// returned by the ledger. A nil error means the proof is valid.
+func VerifyInclusion(hash []byte, index, treeSize int64, path [][]byte, root []byte) error {
+	if index < 1 || index > treeSize {
+		return fmt.Errorf("Index %d out of range for tree size %d", index, treeSize)
+	}
+
+	// Walk from the leaf to the root, tracking the position of the current
+	// node (fn) and of the last node (sn) on each level.
+	fn, sn := index-1, treeSize-1
+	r := apimerkle.LeafHash(hash)
+	for _, p := range path {
+		if sn == 0 {
+			return fmt.Errorf("Audit path too long (%d hashes)", len(path))
+		}
+		if fn&1 == 1 || fn == sn {
+			r = apimerkle.NodeHash(p, r)
+			// Skip levels where the node has no sibling to its right.
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = apimerkle.NodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return fmt.Errorf("Audit path too short (%d hashes)", len(path))
+	}
+	if !bytes.Equal(r, root) {
+		return fmt.Errorf("Root hash mismatch (got %x, expected %x)", r, root)
+	}
+	return nil
+}
+
+// VerifyConsistency checks that the tree of size from, with root hash
+// fromRoot, is a prefix of the tree of size to, with root hash toRoot, using
+// the consistency proof returned by the ledger. This proves that the ledger
+// has only appended transactions between the two sizes. A nil error means the
+// proof is valid.
+func VerifyConsistency(from, to int64, fromRoot, toRoot []byte, proof [][]byte) error {
+	if from < 0 || from > to {
+		return fmt.Errorf("Invalid tree sizes %d and %d", from, to)
+	}
+	if from == to || from == 0 {
+		// Every tree is consistent with itself and with the empty tree.
+		if len(proof) > 0 {
+			return fmt.Errorf("Proof too long (%d hashes)", len(proof))
+		}
+		if from == to && !bytes.Equal(fromRoot, toRoot) {
+			return fmt.Errorf("Root hash mismatch (got %x, expected %x)", toRoot, fromRoot)
+		}
+		return nil
+	}
+
+	// If the older tree is complete, its root is the first node of the proof.
+	if from&(from-1) == 0 {
+		proof = append([][]byte{fromRoot}, proof...)
+	}
+	if len(proof) == 0 {
+		return fmt.Errorf("Proof too short (0 hashes)")
+	}
+
+	// Walk from the last leaf of the older tree to the root, calculating both
+	// roots and tracking the position of the current node (fn) and of the last
+	// node (sn) on each level.
+	fn, sn := from-1, to-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return fmt.Errorf("Proof too long (%d hashes)", len(proof))
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = apimerkle.NodeHash(c, fr)
+			sr = apimerkle.NodeHash(c, sr)
+			// Skip levels where the node has no sibling to its right.
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = apimerkle.NodeHash(sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return fmt.Errorf("Proof too short (%d hashes)", len(proof))
+	}
+	if !bytes.Equal(fr, fromRoot) {
+		return fmt.Errorf("Old root hash mismatch (got %x, expected %x)", fr, fromRoot)
+	}
+	if !bytes.Equal(sr, toRoot) {
+		return fmt.Errorf("New root hash mismatch (got %x, expected %x)", sr, toRoot)
+	}
+	return nil
+}
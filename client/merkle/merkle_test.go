@@ -0,0 +1,74 @@
+package merkle_test
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	apimerkle "github.com/symbiont-io/assembly-sdk/api/merkle"
+	"github.com/symbiont-io/assembly-sdk/client/merkle"
+	"github.com/symbiont-io/assembly-sdk/mock"
+
+	"github.com/nbio/st"
+	"github.com/symbiont-io/assembly-sdk/test/utils"
+	"testing"
+)
+
+func TestVerifyInclusion(t *testing.T) {
+	l := mock.NewLedger()
+	ctx := context.Background()
+	txs := utils.RandomUnsequencedTransactions(13, 100)
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	read, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 13})
+	st.Assert(t, err, nil)
+
+	var hashes [][]byte
+	for _, tx := range read.Transactions {
+		hashes = append(hashes, tx.Hash)
+	}
+	for size := int64(1); size <= 13; size++ {
+		root := apimerkle.RootHash(hashes[:size])
+		for index := int64(1); index <= size; index++ {
+			p, err := l.GetInclusionProof(ctx, &api.GetInclusionProofRequest{Index: index, TreeSize: size})
+			st.Assert(t, err, nil)
+			st.Expect(t, p.Root, root)
+			st.Expect(t, merkle.VerifyInclusion(hashes[index-1], index, size, p.AuditPath, root), nil)
+
+			// The proof must not verify for another transaction or index.
+			if size > 1 {
+				other := index%size + 1
+				st.Refute(t, merkle.VerifyInclusion(hashes[other-1], index, size, p.AuditPath, root), nil)
+				st.Refute(t, merkle.VerifyInclusion(hashes[index-1], other, size, p.AuditPath, root), nil)
+			}
+		}
+	}
+}
+
+func TestVerifyConsistency(t *testing.T) {
+	l := mock.NewLedger()
+	ctx := context.Background()
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(13, 100),
+	})
+	st.Assert(t, err, nil)
+
+	roots := [][]byte{apimerkle.RootHash(nil)}
+	for size := int64(1); size <= 13; size++ {
+		p, err := l.GetConsistencyProof(ctx, &api.GetConsistencyProofRequest{From: size, To: size})
+		st.Assert(t, err, nil)
+		roots = append(roots, p.ToRoot)
+	}
+	for to := int64(1); to <= 13; to++ {
+		for from := int64(0); from <= to; from++ {
+			p, err := l.GetConsistencyProof(ctx, &api.GetConsistencyProofRequest{From: from, To: to})
+			st.Assert(t, err, nil)
+			st.Expect(t, merkle.VerifyConsistency(from, to, roots[from], roots[to], p.Proof), nil)
+
+			// The proof must not verify for other roots.
+			if from > 0 && from < to {
+				st.Refute(t, merkle.VerifyConsistency(from, to, roots[from-1], roots[to], p.Proof), nil)
+				st.Refute(t, merkle.VerifyConsistency(from, to, roots[from], roots[to-1], p.Proof), nil)
+			}
+		}
+	}
+}
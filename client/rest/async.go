@@ -0,0 +1,200 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/context"
+	"net/http"
+	"time"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/api/rest"
+)
+
+// NotSequencedError is the error returned by PendingAppend.Wait when some of
+// the transactions haven't been sequenced before the sequencing timeout. The
+// ledger doesn't guarantee that asynchronously appended transactions are ever
+// written, so the caller should resubmit them.
+type NotSequencedError struct {
+	// Transactions are the transactions that weren't seen on the ledger, in
+	// the order of the append request.
+	Transactions []*api.UnsequencedTransaction
+
+	// Result holds the receipts of the transactions that were sequenced, with
+	// nil receipts for the missing ones.
+	Result *api.AppendResult
+}
+
+func (e NotSequencedError) Error() string {
+	return fmt.Sprintf("%d transactions not sequenced within timeout", len(e.Transactions))
+}
+func (e NotSequencedError) Timeout() bool   { return true }
+func (e NotSequencedError) Temporary() bool { return true }
+
+// PendingAppend is a handle to transactions appended without waiting for them
+// to be sequenced. It resolves once all transactions have been seen on the
+// ledger, or the sequencing timeout has passed.
+type PendingAppend struct {
+	done   chan struct{}
+	result *api.AppendResult
+	err    error
+}
+
+// Done returns a channel that's closed when the append has resolved.
+func (p *PendingAppend) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait waits for the append to resolve, or for the context to be done. The
+// result holds the index the transactions were written with as LastIndex, and
+// a receipt for each transaction in the order of the request. If some
+// transactions weren't sequenced in time, a NotSequencedError is returned.
+func (p *PendingAppend) Wait(ctx context.Context) (*api.AppendResult, error) {
+	select {
+	case <-p.done:
+		return p.result, p.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// AppendTransactionsAsync appends an array of transactions to the ledger
+// without waiting for them to be sequenced. It returns as soon as the ledger
+// has accepted the request, with a handle that tracks the transactions by
+// reading the ledger until all their hashes have been seen, or the sequencing
+// timeout has passed. The tracking stops early if the context is done, in
+// which case the append resolves with the context's error. If a network seed
+// is provided it will be checked against the ledger's, both when appending and
+// while tracking. Conditional appends can't be made asynchronously, since a
+// last index mismatch couldn't be reported.
+func (c *Client) AppendTransactionsAsync(ctx context.Context, req *api.AppendRequest) (*PendingAppend, error) {
+	if req.ExpectedLastIndex != nil {
+		return nil, api.BadRequestError("Asynchronous appends can't have an expected last index")
+	}
+
+	// Find where to start looking for the transactions before appending them.
+	status, err := c.ServerStatus(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get server status: %v", err)
+	}
+	if len(req.NetworkSeed) > 0 && !bytes.Equal(req.NetworkSeed, status.NetworkSeed) {
+		return nil, api.NetworkSeedMismatchError(status.NetworkSeed)
+	}
+	deadline := time.Now().Add(c.options.sequencingTimeout)
+	trackCtx := ctx
+
+	ctx, url := c.genAppendContextAndURL(ctx)
+
+	// Post encoded transactions to the ledger, asking it not to wait for them
+	// to be sequenced.
+	url += "?async=true"
+	resp, err := c.postAppend(ctx, url, req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Decode and check result.
+	res := rest.AppendResult{}
+	dec := json.NewDecoder(resp.Body)
+	if err := dec.Decode(&res); err != nil {
+		return nil, fmt.Errorf("Failed to decode response (code %d): %v", resp.StatusCode, err)
+	}
+	seed, err := decodeAndVerifyNetworkSeed(resp.Header, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode network seed in response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newError(resp.StatusCode, res.Error, seed)
+	}
+
+	p := &PendingAppend{done: make(chan struct{})}
+	go c.track(trackCtx, p, req.Transactions, status.NetworkSeed, status.LastIndex+1, deadline)
+	return p, nil
+}
+
+// trackRetryPeriod is the time to wait before retrying a failed read while
+// tracking asynchronously appended transactions.
+const trackRetryPeriod = time.Second
+
+// track reads the ledger from index until all transactions have been seen, the
+// deadline passes or the context is done, then resolves the pending append.
+func (c *Client) track(parent context.Context, p *PendingAppend, txs []*api.UnsequencedTransaction, seed []byte, index int64, deadline time.Time) {
+	defer close(p.done)
+	ctx, cancel := context.WithDeadline(parent, deadline)
+	defer cancel()
+
+	// Map hashes to the positions of the transactions in the request, and
+	// only read the types of transactions that were appended.
+	positions := make(map[string][]int)
+	var types []string
+	seen := make(map[string]bool)
+	for i, tx := range txs {
+		positions[string(tx.Hash)] = append(positions[string(tx.Hash)], i)
+		if !seen[tx.Type] {
+			seen[tx.Type] = true
+			types = append(types, tx.Type)
+		}
+	}
+	p.result = &api.AppendResult{
+		NetworkSeed: seed,
+		Receipts:    make([]*api.Receipt, len(txs)),
+	}
+	missing := len(txs)
+
+	for missing > 0 && ctx.Err() == nil {
+		res, err := c.ReadTransactions(ctx, &api.ReadRequest{
+			NetworkSeed: seed,
+			Index:       index,
+			Types:       types,
+		})
+		if err != nil {
+			if _, ok := err.(api.NetworkSeedMismatchError); ok {
+				p.err = err
+				return
+			}
+			// Retry other errors until the deadline.
+			select {
+			case <-time.After(trackRetryPeriod):
+			case <-ctx.Done():
+			}
+			continue
+		}
+		for _, tx := range res.Transactions {
+			pos := positions[string(tx.Hash)]
+			if len(pos) == 0 {
+				continue
+			}
+			positions[string(tx.Hash)] = pos[1:]
+			p.result.Receipts[pos[0]] = &api.Receipt{
+				Index:     tx.Index,
+				Hash:      tx.Hash,
+				StateHash: tx.StateHash,
+				Timestamp: tx.Timestamp,
+			}
+			if tx.Index > p.result.LastIndex {
+				p.result.LastIndex = tx.Index
+			}
+			missing--
+		}
+		if res.LastIndex >= index {
+			index = res.LastIndex + 1
+		}
+	}
+
+	if missing > 0 && parent.Err() != nil {
+		// The caller gave up before the deadline.
+		p.err = parent.Err()
+		return
+	}
+	if missing > 0 {
+		e := NotSequencedError{Result: p.result}
+		for i, r := range p.result.Receipts {
+			if r == nil {
+				e.Transactions = append(e.Transactions, txs[i])
+			}
+		}
+		p.err = e
+	}
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/symbiont-io/assembly-sdk/api"
 	"github.com/symbiont-io/assembly-sdk/api/rest"
+	"github.com/symbiont-io/assembly-sdk/client/merkle"
 )
 
 // Client is a ledger API client.
@@ -54,6 +55,9 @@ func (c *Client) genReadContextAndURL(ctx context.Context, req *api.ReadRequest)
 		count = c.options.maxCount
 	}
 	p.Add("max_count", strconv.FormatInt(int64(count), 10))
+	for _, t := range req.Types {
+		p.Add("type", t)
+	}
 
 	pollTimeout := c.options.pollTimeout
 	deadline, ok := ctx.Deadline()
@@ -70,6 +74,18 @@ func (c *Client) genReadContextAndURL(ctx context.Context, req *api.ReadRequest)
 	return ctx, u.String()
 }
 
+// acceptProtobuf is the Accept header asking for protobuf encoded responses,
+// which servers not supporting them ignore and respond to in JSON.
+const acceptProtobuf = rest.ProtobufContentType + ", application/json;q=0.5"
+
+// setAccept sets the Accept header of the request, if the client is to use
+// protobuf.
+func (c *Client) setAccept(r *http.Request) {
+	if c.options.protobuf {
+		r.Header.Set("Accept", acceptProtobuf)
+	}
+}
+
 // decodeAndVerifyNetworkSeed reads the received network seed from the header
 // and compares verifies that it matches the expectation.
 func decodeAndVerifyNetworkSeed(h http.Header, expected []byte) ([]byte, error) {
@@ -106,6 +122,9 @@ func newError(code int, msg string, seed []byte) error {
 // returned. If a network seed is provided, it will be checked against the
 // ledger's, and an error returned in case of a mismatch.
 //
+// If types are requested, only matching transactions are returned and the
+// result's LastIndex tells where to continue reading from.
+//
 // The function returns the server's network seed, an array of sequenced
 // transactions with the index of the first matching the requested index, or
 // potentially an error. If there's a network seed mismatch, the caller should,
@@ -121,21 +140,193 @@ func (c *Client) ReadTransactions(ctx context.Context, req *api.ReadRequest) (*a
 	}
 	r = r.WithContext(ctx)
 	r.Header.Add(rest.SymbiontNetworkSeedHeader, hex.EncodeToString(req.NetworkSeed))
+	c.setAccept(r)
 
-	client := &http.Client{}
-	resp, err := client.Do(r)
+	resp, err := c.do(r)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to send GET request to %q: %v", c.host, err)
 	}
 	defer resp.Body.Close()
 
-	// Parse result.
+	// Parse result, in protobuf if the server sent it.
 	var res rest.ReadResult
+	var pb *api.ReadResult
+	if resp.StatusCode == http.StatusOK && rest.IsProtobuf(resp.Header) {
+		pb, err = rest.DecodeProtobufReadResult(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode response: %v", err)
+		}
+		res.FirstIndex = req.Index
+		res.LastIndex = pb.LastIndex
+	} else {
+		dec := json.NewDecoder(resp.Body)
+		if err := dec.Decode(&res); err != nil {
+			return nil, fmt.Errorf("Failed to decode response: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			seed, _ := decodeAndVerifyNetworkSeed(resp.Header, nil)
+			return nil, newError(resp.StatusCode, res.Error, seed)
+		}
+	}
+	seed, err := decodeAndVerifyNetworkSeed(resp.Header, req.NetworkSeed)
+	if err != nil {
+		return nil, err
+	}
+	// Verify returned seed and index, and decode received transactions.
+	if res.FirstIndex != req.Index {
+		return nil, fmt.Errorf("Unexpected \"first_index\" (got %d, expected %d)",
+			res.FirstIndex, req.Index)
+	}
+	var txs []*api.SequencedTransaction
+	if pb != nil {
+		txs = pb.Transactions
+	} else {
+		txs, err = rest.DecodeSequencedTransactions(res.Transactions)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode transactions: %v", err)
+		}
+	}
+	if len(req.Types) == 0 {
+		if len(txs) > 0 && txs[0].Index != req.Index {
+			return nil, fmt.Errorf("Unexpected index of first tx (got %d, expected %d)",
+				txs[0].Index, req.Index)
+		}
+	} else {
+		// Filtered transactions must be in order within the scanned range.
+		// Servers that don't support filtering ignore the types and don't
+		// report the scanned range, so the range ends with the last
+		// transaction and mismatching ones are filtered out here.
+		if n := len(txs); n > 0 && res.LastIndex < req.Index {
+			res.LastIndex = txs[n-1].Index
+		}
+		index := req.Index
+		matching := txs[:0]
+		for i, tx := range txs {
+			if tx.Index < index || tx.Index > res.LastIndex {
+				return nil, fmt.Errorf("Unexpected index of tx %d (got %d, expected [%d, %d])",
+					i, tx.Index, index, res.LastIndex)
+			}
+			if api.MatchesType(tx.Type, req.Types) {
+				matching = append(matching, tx)
+			}
+			index = tx.Index + 1
+		}
+		txs = matching
+	}
+	return &api.ReadResult{
+		NetworkSeed:  seed,
+		Transactions: txs,
+		LastIndex:    res.LastIndex,
+	}, nil
+}
+
+// GetTransaction looks up a transaction by its hash. If the same transaction
+// has been appended more than once, the first one sequenced is returned. If
+// there's no transaction with the hash a NotFoundError is returned. If a
+// network seed is provided, it will be checked against the ledger's, and an
+// error returned in case of a mismatch.
+func (c *Client) GetTransaction(ctx context.Context, req *api.GetTransactionRequest) (*api.GetTransactionResult, error) {
+	u, err := url.Parse(c.host)
+	if err != nil {
+		c.fatalf("Failed to parse host %q: %v", c.host, err)
+	}
+	u.Path += rest.URLPrefix + "/by-hash/" + hex.EncodeToString(req.Hash)
+
+	// Set default timeout if none is provided.
+	if _, ok := ctx.Deadline(); !ok {
+		ctx, _ = context.WithTimeout(ctx, c.options.callTimeout)
+	}
+
+	// Perform GET request.
+	r, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create GET request to %q: %v", c.host, err)
+	}
+	r = r.WithContext(ctx)
+	r.Header.Add(rest.SymbiontNetworkSeedHeader, hex.EncodeToString(req.NetworkSeed))
+
+	resp, err := c.do(r)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to send GET request to %q: %v", c.host, err)
+	}
+	defer resp.Body.Close()
+
+	// Parse result.
+	var res rest.TransactionResult
 	dec := json.NewDecoder(resp.Body)
 	if err := dec.Decode(&res); err != nil {
 		return nil, fmt.Errorf("Failed to decode response: %v", err)
 	}
+	if resp.StatusCode != http.StatusOK {
+		seed, _ := decodeAndVerifyNetworkSeed(resp.Header, nil)
+		return nil, newError(resp.StatusCode, res.Error, seed)
+	}
+	seed, err := decodeAndVerifyNetworkSeed(resp.Header, req.NetworkSeed)
+	if err != nil {
+		return nil, err
+	}
 
+	// Decode and verify the received transaction.
+	if res.EncodedSequencedTransaction == nil {
+		return nil, fmt.Errorf("No transaction in response")
+	}
+	txs, err := rest.DecodeSequencedTransactions(
+		[]*rest.EncodedSequencedTransaction{res.EncodedSequencedTransaction})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode transaction: %v", err)
+	}
+	if !bytes.Equal(txs[0].Hash, req.Hash) {
+		return nil, fmt.Errorf("Unexpected hash of transaction (got %x, expected %x)",
+			txs[0].Hash, req.Hash)
+	}
+	return &api.GetTransactionResult{
+		NetworkSeed: seed,
+		Transaction: txs[0],
+	}, nil
+}
+
+// GetInclusionProof requests the audit path proving that the transaction at
+// the requested index is included in the ledger's Merkle tree, of the
+// requested size or up to the last index if none is set. The proof is verified
+// against the root hash in the result before it's returned; callers should
+// compare that root with one they trust, eg. the MerkleRoot of a status. If a
+// network seed is provided, it will be checked against the ledger's, and an
+// error returned in case of a mismatch.
+func (c *Client) GetInclusionProof(ctx context.Context, req *api.GetInclusionProofRequest) (*api.GetInclusionProofResult, error) {
+	u, err := url.Parse(c.host)
+	if err != nil {
+		c.fatalf("Failed to parse host %q: %v", c.host, err)
+	}
+	u.Path += rest.URLPrefix + "/" + strconv.FormatInt(req.Index, 10) + "/proof"
+	if req.TreeSize != 0 {
+		u.RawQuery = url.Values{"tree_size": {strconv.FormatInt(req.TreeSize, 10)}}.Encode()
+	}
+
+	// Set default timeout if none is provided.
+	if _, ok := ctx.Deadline(); !ok {
+		ctx, _ = context.WithTimeout(ctx, c.options.callTimeout)
+	}
+
+	// Perform GET request.
+	r, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create GET request to %q: %v", c.host, err)
+	}
+	r = r.WithContext(ctx)
+	r.Header.Add(rest.SymbiontNetworkSeedHeader, hex.EncodeToString(req.NetworkSeed))
+
+	resp, err := c.do(r)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to send GET request to %q: %v", c.host, err)
+	}
+	defer resp.Body.Close()
+
+	// Parse result.
+	var res rest.InclusionProofResult
+	dec := json.NewDecoder(resp.Body)
+	if err := dec.Decode(&res); err != nil {
+		return nil, fmt.Errorf("Failed to decode response: %v", err)
+	}
 	if resp.StatusCode != http.StatusOK {
 		seed, _ := decodeAndVerifyNetworkSeed(resp.Header, nil)
 		return nil, newError(resp.StatusCode, res.Error, seed)
@@ -144,22 +335,90 @@ func (c *Client) ReadTransactions(ctx context.Context, req *api.ReadRequest) (*a
 	if err != nil {
 		return nil, err
 	}
-	// Verify returned seed and index, and decode received transactions.
-	if res.FirstIndex != req.Index {
-		return nil, fmt.Errorf("Unexpected \"first_index\" (got %d, expected %d)",
-			res.FirstIndex, req.Index)
+
+	// Decode and verify the proof.
+	proof, err := rest.DecodeInclusionProof(&res)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode proof: %v", err)
+	}
+	proof.NetworkSeed = seed
+	if proof.Index != req.Index || (req.TreeSize != 0 && proof.TreeSize != req.TreeSize) {
+		return nil, fmt.Errorf("Unexpected proof (got index %d in tree size %d)", proof.Index, proof.TreeSize)
 	}
-	txs, err := rest.DecodeSequencedTransactions(res.Transactions)
+	err = merkle.VerifyInclusion(proof.Hash, proof.Index, proof.TreeSize, proof.AuditPath, proof.Root)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to decode transactions: %v", err)
+		return nil, fmt.Errorf("Invalid inclusion proof: %v", err)
 	}
-	if len(txs) > 0 {
-		if txs[0].Index != req.Index {
-			return nil, fmt.Errorf("Unexpected index of first tx (got %d, expected %d)",
-				txs[0].Index, req.Index)
-		}
+	return proof, nil
+}
+
+// GetConsistencyProof requests the proof that the ledger's Merkle tree of the
+// requested from size is a prefix of the tree of the requested to size, or up
+// to the last index if none is set. The proof is verified against the root
+// hashes in the result before it's returned; callers should compare the older
+// root with one they recorded earlier. If a network seed is provided, it will
+// be checked against the ledger's, and an error returned in case of a
+// mismatch.
+func (c *Client) GetConsistencyProof(ctx context.Context, req *api.GetConsistencyProofRequest) (*api.GetConsistencyProofResult, error) {
+	u, err := url.Parse(c.host)
+	if err != nil {
+		c.fatalf("Failed to parse host %q: %v", c.host, err)
+	}
+	u.Path += "/proofs/consistency"
+	q := url.Values{"from": {strconv.FormatInt(req.From, 10)}}
+	if req.To != 0 {
+		q.Set("to", strconv.FormatInt(req.To, 10))
+	}
+	u.RawQuery = q.Encode()
+
+	// Set default timeout if none is provided.
+	if _, ok := ctx.Deadline(); !ok {
+		ctx, _ = context.WithTimeout(ctx, c.options.callTimeout)
+	}
+
+	// Perform GET request.
+	r, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create GET request to %q: %v", c.host, err)
+	}
+	r = r.WithContext(ctx)
+	r.Header.Add(rest.SymbiontNetworkSeedHeader, hex.EncodeToString(req.NetworkSeed))
+
+	resp, err := c.do(r)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to send GET request to %q: %v", c.host, err)
+	}
+	defer resp.Body.Close()
+
+	// Parse result.
+	var res rest.ConsistencyProofResult
+	dec := json.NewDecoder(resp.Body)
+	if err := dec.Decode(&res); err != nil {
+		return nil, fmt.Errorf("Failed to decode response: %v", err)
 	}
-	return &api.ReadResult{seed, txs}, nil
+	if resp.StatusCode != http.StatusOK {
+		seed, _ := decodeAndVerifyNetworkSeed(resp.Header, nil)
+		return nil, newError(resp.StatusCode, res.Error, seed)
+	}
+	seed, err := decodeAndVerifyNetworkSeed(resp.Header, req.NetworkSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode and verify the proof.
+	proof, err := rest.DecodeConsistencyProof(&res)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode proof: %v", err)
+	}
+	proof.NetworkSeed = seed
+	if proof.From != req.From || (req.To != 0 && proof.To != req.To) {
+		return nil, fmt.Errorf("Unexpected proof (got tree sizes %d to %d)", proof.From, proof.To)
+	}
+	err = merkle.VerifyConsistency(proof.From, proof.To, proof.FromRoot, proof.ToRoot, proof.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid consistency proof: %v", err)
+	}
+	return proof, nil
 }
 
 // genAppendContextAndURL generates the context and URL for an append call.
@@ -185,48 +444,163 @@ func (c *Client) genAppendContextAndURL(ctx context.Context) (context.Context, s
 // network seed is provided this will be checked against the ledger's and
 // requests with a mismatching network seed will be rejected. Both successful
 // requests and those rejected due to seed mismatch will return the server's
-// network seed.
+// network seed. If the ledger provides them, the result holds a receipt for
+// each transaction, in the order of the request, telling where it was written.
+// If an expected last index is set and the ledger has moved past it, an
+// api.ConflictError holding the ledger's last index is returned. If the client
+// has a trusted key, the ledger must return receipts signed with it.
 func (c *Client) AppendTransactions(ctx context.Context, req *api.AppendRequest) (*api.AppendResult, error) {
-	// Encode transactions and calculate their hashes to protect against corruption.
-	data, err := rest.EncodeAppendRequest(req)
+	ctx, url := c.genAppendContextAndURL(ctx)
+	if e := req.ExpectedLastIndex; e != nil {
+		url += "?expected_last_index=" + strconv.FormatInt(e.Index, 10)
+	}
+
+	// Post encoded transactions to the ledger.
+	resp, err := c.postAppend(ctx, url, req, c.options.protobuf)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Decode and check result, in protobuf if the server sent it.
+	seed, err := decodeAndVerifyNetworkSeed(resp.Header, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode network seed in response: %v", err)
+	}
+	var lastIndex int64
+	var receipts []*api.Receipt
+	if resp.StatusCode == http.StatusOK && rest.IsProtobuf(resp.Header) {
+		var res api.AppendResult
+		if err := rest.DecodeProtobuf(resp.Body, &res); err != nil {
+			return nil, fmt.Errorf("Failed to decode response (code %d): %v", resp.StatusCode, err)
+		}
+		lastIndex, receipts = res.LastIndex, res.Receipts
+	} else {
+		res := rest.AppendResult{}
+		dec := json.NewDecoder(resp.Body)
+		if err := dec.Decode(&res); err != nil {
+			return nil, fmt.Errorf("Failed to decode response (code %d): %v", resp.StatusCode, err)
+		}
+		if resp.StatusCode == http.StatusConflict {
+			last, err := strconv.ParseInt(resp.Header.Get(rest.SymbiontLastIndexHeader), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse last index in response: %v", err)
+			}
+			return nil, api.ConflictError(last)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, newError(resp.StatusCode, res.Error, seed)
+		}
+		lastIndex = res.LastIndex
+		receipts, err = rest.DecodeReceipts(res.Receipts)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode receipts: %v", err)
+		}
+	}
+
+	// Verify that receipts, if provided, match the request.
+	if len(receipts) > 0 {
+		if len(receipts) != len(req.Transactions) {
+			return nil, fmt.Errorf("Unexpected number of receipts (got %d, expected %d)",
+				len(receipts), len(req.Transactions))
+		}
+		for i, r := range receipts {
+			if !bytes.Equal(r.Hash, req.Transactions[i].Hash) {
+				return nil, fmt.Errorf("Hash mismatch on receipt %d", i)
+			}
+			if r.Index > lastIndex {
+				return nil, fmt.Errorf("Unexpected index of receipt %d (got %d, last index %d)",
+					i, r.Index, lastIndex)
+			}
+		}
+	}
+
+	// Verify that the receipts were signed by the trusted ledger node.
+	if key := c.options.trustedKey; key != nil {
+		if len(receipts) == 0 {
+			return nil, fmt.Errorf("No signed receipts in response")
+		}
+		for i, r := range receipts {
+			if err := api.VerifyReceipt(key, seed, r); err != nil {
+				return nil, fmt.Errorf("Failed to verify receipt %d: %v", i, err)
+			}
+		}
+	}
+	return &api.AppendResult{
+		NetworkSeed: seed,
+		LastIndex:   lastIndex,
+		Receipts:    receipts,
+	}, nil
+}
+
+// postAppend posts the transactions of an append request to the URL, encoded
+// in protobuf or JSON, and compressed if larger than the compression
+// threshold. Older servers fail to parse protobuf or compressed requests,
+// which are then sent again in a way they support.
+func (c *Client) postAppend(ctx context.Context, url string, req *api.AppendRequest, protobuf bool) (*http.Response, error) {
+	resp, err := c.sendAppend(ctx, url, req, protobuf, true)
+	if err != nil || resp.StatusCode != http.StatusBadRequest {
+		return resp, err
+	}
+	sent := resp.Request.Header
+	compress := serverSupports(resp.Header, gzipVersion)
+	if protobuf && !serverSupports(resp.Header, protobufVersion) {
+		protobuf = false
+	} else if sent.Get("Content-Encoding") != "gzip" || compress {
+		return resp, nil
+	}
+	resp.Body.Close()
+	return c.sendAppend(ctx, url, req, protobuf, compress)
+}
+
+// sendAppend posts the transactions of an append request to the URL, encoded
+// in protobuf or JSON, and compressed if allowed and larger than the
+// compression threshold. Transaction hashes are calculated, if not set, to
+// protect against corruption.
+func (c *Client) sendAppend(ctx context.Context, url string, req *api.AppendRequest, protobuf, compress bool) (*http.Response, error) {
+	var data []byte
+	var err error
+	contentType := "application/json"
+	if protobuf {
+		data, err = rest.EncodeProtobufAppendRequest(req)
+		contentType = rest.ProtobufContentType
+	} else {
+		data, err = rest.EncodeAppendRequest(req)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Failed to encode request: %v", err)
 	}
-	ctx, url := c.genAppendContextAndURL(ctx)
+	compress = compress && c.options.compressThreshold >= 0 && len(data) >= c.options.compressThreshold
+	if compress {
+		data, err = gzipData(data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to compress request: %v", err)
+		}
+	}
 
-	// Post encoded transactions to the ledger.
 	r, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create POST request to %q: %v", c.host, err)
 	}
 	r = r.WithContext(ctx)
-	r.Header.Add("Content-Type", "application/json")
+	r.Header.Add("Content-Type", contentType)
+	if compress {
+		r.Header.Add("Content-Encoding", "gzip")
+	}
 	r.Header.Add(rest.SymbiontNetworkSeedHeader, hex.EncodeToString(req.NetworkSeed))
-
-	client := &http.Client{}
-	resp, err := client.Do(r)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to send POST request to %q: %v", c.host, err)
+	if protobuf {
+		r.Header.Set("Accept", acceptProtobuf)
 	}
-	defer resp.Body.Close()
 
-	// Decode and check result.
-	res := rest.AppendResult{}
-	dec := json.NewDecoder(resp.Body)
-	if err := dec.Decode(&res); err != nil {
-		return nil, fmt.Errorf("Failed to decode response (code %d): %v", resp.StatusCode, err)
-	}
-	seed, err := decodeAndVerifyNetworkSeed(resp.Header, nil)
+	resp, err := c.do(r)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to decode network seed in response: %v", err)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, newError(resp.StatusCode, res.Error, seed)
+		return nil, fmt.Errorf("Failed to send POST request to %q: %v", c.host, err)
 	}
-	return &api.AppendResult{seed, res.LastIndex}, nil
+	return resp, nil
 }
 
-// ServerStatus return the status of the node the client is connected to.
+// ServerStatus return the status of the node the client is connected to. If
+// the client has a trusted key, the signature of the status is verified.
 func (c *Client) ServerStatus(ctx context.Context, _ *api.Empty) (*api.ServerStatusResult, error) {
 	// Set default timeout if none is provided.
 	_, ok := ctx.Deadline()
@@ -235,26 +609,45 @@ func (c *Client) ServerStatus(ctx context.Context, _ *api.Empty) (*api.ServerSta
 	}
 
 	// Perform request.
-	client := &http.Client{}
 	r, err := http.NewRequest("GET", c.host, nil)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create GET request to %q: %v", c.host, err)
 	}
 	r = r.WithContext(ctx)
-	resp, err := client.Do(r)
+	c.setAccept(r)
+	resp, err := c.do(r)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to send get request to %q: %v", c.host, err)
 	}
 	defer resp.Body.Close()
 
-	// Decode and check result.
-	dec := json.NewDecoder(resp.Body)
-	var res rest.ServerStatusResult
-	if err := dec.Decode(&res); err != nil {
-		return nil, fmt.Errorf("Failed to decode response: %v", err)
+	// Decode and check result, in protobuf if the server sent it.
+	var status *api.ServerStatusResult
+	if resp.StatusCode == http.StatusOK && rest.IsProtobuf(resp.Header) {
+		status = &api.ServerStatusResult{}
+		if err := rest.DecodeProtobuf(resp.Body, status); err != nil {
+			return nil, fmt.Errorf("Failed to decode response: %v", err)
+		}
+	} else {
+		dec := json.NewDecoder(resp.Body)
+		var res rest.ServerStatusResult
+		if err := dec.Decode(&res); err != nil {
+			return nil, fmt.Errorf("Failed to decode response: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, newError(resp.StatusCode, res.Error, nil)
+		}
+		status, err = rest.DecodeServerStatus(&res)
+		if err != nil {
+			return nil, err
+		}
 	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, newError(resp.StatusCode, res.Error, nil)
+
+	// Verify that the status was signed by the trusted ledger node.
+	if key := c.options.trustedKey; key != nil {
+		if err := api.VerifyStatus(key, status); err != nil {
+			return nil, fmt.Errorf("Failed to verify status: %v", err)
+		}
 	}
-	return rest.DecodeServerStatus(&res)
+	return status, nil
 }
@@ -1,9 +1,12 @@
 package client_test
 
 import (
+	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/gorilla/schema"
+	"golang.org/x/crypto/ed25519"
 	"golang.org/x/net/context"
 	"net/http"
 	"time"
@@ -11,8 +14,10 @@ import (
 	"github.com/symbiont-io/assembly-sdk/api"
 	"github.com/symbiont-io/assembly-sdk/api/rest"
 	"github.com/symbiont-io/assembly-sdk/client/rest"
+	"github.com/symbiont-io/assembly-sdk/mock"
 
 	"github.com/nbio/st"
+	"github.com/symbiont-io/assembly-sdk/test/utils"
 	"net/http/httptest"
 	"testing"
 )
@@ -178,6 +183,84 @@ func TestClientReadBadHash(t *testing.T) {
 	st.Refute(t, err, nil)
 }
 
+func TestClientReadTypes(t *testing.T) {
+	l := mock.NewLedger()
+	s := httptest.NewServer(rest.NewServer(l).Router())
+	defer s.Close()
+	c := client.New(s.URL)
+
+	ctx := context.Background()
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: []*api.UnsequencedTransaction{
+			{Type: "a/x", Data: []byte("1")},
+			{Type: "b", Data: []byte("2")},
+			{Type: "c", Data: []byte("3")},
+			{Type: "a/y", Data: []byte("4")},
+			{Type: "b", Data: []byte("5")},
+		},
+	})
+	st.Assert(t, err, nil)
+
+	res, err := c.ReadTransactions(ctx, &api.ReadRequest{
+		Index: 1,
+		Types: []string{"a/*", "c"},
+	})
+	st.Assert(t, err, nil)
+	st.Assert(t, len(res.Transactions), 3)
+	st.Expect(t, res.Transactions[0].Index, int64(1))
+	st.Expect(t, res.Transactions[1].Index, int64(3))
+	st.Expect(t, res.Transactions[2].Index, int64(4))
+	st.Expect(t, res.LastIndex, int64(5))
+}
+
+func TestClientReadTypesUnsupported(t *testing.T) {
+	// The server ignores the types, so the client filters the transactions
+	// itself.
+	m := mockReadServer{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		q.Del("type")
+		r.URL.RawQuery = q.Encode()
+		m.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+	c := client.New(s.URL)
+
+	res, err := c.ReadTransactions(context.Background(), &api.ReadRequest{
+		Index: 1,
+		Types: []string{"a/*"},
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, len(res.Transactions), 0)
+	st.Expect(t, res.LastIndex, int64(2))
+}
+
+func TestClientGetTransaction(t *testing.T) {
+	l := mock.NewLedger()
+	s := httptest.NewServer(rest.NewServer(l).Router())
+	defer s.Close()
+	c := client.New(s.URL)
+
+	ctx := context.Background()
+	status, _ := l.ServerStatus(ctx, nil)
+	req := &api.AppendRequest{Transactions: utils.RandomUnsequencedTransactions(3, 100)}
+	_, err := c.AppendTransactions(ctx, req)
+	st.Assert(t, err, nil)
+
+	res, err := c.GetTransaction(ctx, &api.GetTransactionRequest{
+		NetworkSeed: status.NetworkSeed,
+		Hash:        req.Transactions[2].Hash,
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.NetworkSeed, status.NetworkSeed)
+	st.Expect(t, res.Transaction.Index, int64(3))
+	st.Expect(t, res.Transaction.Data, req.Transactions[2].Data)
+
+	_, err = c.GetTransaction(ctx, &api.GetTransactionRequest{Hash: []byte("unknown")})
+	_, ok := err.(api.NotFoundError)
+	st.Expect(t, ok, true)
+}
+
 type appendParams struct {
 	Async bool `schema:"async"`
 }
@@ -210,6 +293,127 @@ func (m *mockAppendServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	`)
 }
 
+func TestClientGetInclusionProof(t *testing.T) {
+	l := mock.NewLedger()
+	s := httptest.NewServer(rest.NewServer(l).Router())
+	defer s.Close()
+	c := client.New(s.URL)
+
+	ctx := context.Background()
+	req := &api.AppendRequest{Transactions: utils.RandomUnsequencedTransactions(7, 100)}
+	_, err := c.AppendTransactions(ctx, req)
+	st.Assert(t, err, nil)
+	status, err := c.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+
+	res, err := c.GetInclusionProof(ctx, &api.GetInclusionProofRequest{Index: 3})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.TreeSize, int64(7))
+	st.Expect(t, res.Hash, req.Transactions[2].Hash)
+	st.Expect(t, res.Root, status.MerkleRoot)
+
+	res, err = c.GetInclusionProof(ctx, &api.GetInclusionProofRequest{Index: 3, TreeSize: 4})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.TreeSize, int64(4))
+
+	_, err = c.GetInclusionProof(ctx, &api.GetInclusionProofRequest{Index: 3, TreeSize: 8})
+	_, ok := err.(api.NotFoundError)
+	st.Assert(t, ok, true)
+}
+
+func TestClientGetConsistencyProof(t *testing.T) {
+	l := mock.NewLedger()
+	s := httptest.NewServer(rest.NewServer(l).Router())
+	defer s.Close()
+	c := client.New(s.URL)
+
+	ctx := context.Background()
+	_, err := c.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(5, 100),
+	})
+	st.Assert(t, err, nil)
+	old, err := c.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+	_, err = c.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(6, 100),
+	})
+	st.Assert(t, err, nil)
+
+	res, err := c.GetConsistencyProof(ctx, &api.GetConsistencyProofRequest{From: old.LastIndex})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.To, int64(11))
+	st.Expect(t, res.FromRoot, old.MerkleRoot)
+
+	_, err = c.GetConsistencyProof(ctx, &api.GetConsistencyProofRequest{From: 5, To: 12})
+	_, ok := err.(api.NotFoundError)
+	st.Assert(t, ok, true)
+}
+
+func TestClientTrustedKey(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	st.Assert(t, err, nil)
+	l := mock.NewLedger(mock.WithSigningKey(key))
+	s := httptest.NewServer(rest.NewServer(l).Router())
+	defer s.Close()
+
+	ctx := context.Background()
+	c := client.New(s.URL, client.WithTrustedKey(pub))
+	status, err := c.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+	st.Expect(t, []byte(status.PublicKey), []byte(pub))
+	res, err := c.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(2, 100),
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, len(res.Receipts), 2)
+
+	// Responses signed with another key are rejected.
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	st.Assert(t, err, nil)
+	c = client.New(s.URL, client.WithTrustedKey(other))
+	_, err = c.ServerStatus(ctx, nil)
+	st.Refute(t, err, nil)
+	_, err = c.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	st.Refute(t, err, nil)
+
+	// So are unsigned responses.
+	s2 := httptest.NewServer(rest.NewServer(mock.NewLedger()).Router())
+	defer s2.Close()
+	c = client.New(s2.URL, client.WithTrustedKey(pub))
+	_, err = c.ServerStatus(ctx, nil)
+	st.Refute(t, err, nil)
+	_, err = c.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	st.Refute(t, err, nil)
+}
+
+type mockBadProofServer struct{}
+
+func (m *mockBadProofServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add(rest.SymbiontNetworkSeedHeader, "736F6D652073656564")
+	fmt.Fprintf(w, `
+		{
+			"tx_index":1,
+			"tree_size":2,
+			"hash":"b94f6f125c79e3a5ffaa826f584c10d52ada669e6762051b826b55776d05aed3",
+			"root":"71a8e55edefe53f703646a679e66799cfef657b98474ff2e4148c3a1ea43169c",
+			"audit_path":["71a8e55edefe53f703646a679e66799cfef657b98474ff2e4148c3a1ea43169c"]
+		}
+	`)
+}
+
+func TestClientGetInclusionProofInvalid(t *testing.T) {
+	s := httptest.NewServer(&mockBadProofServer{})
+	defer s.Close()
+	c := client.New(s.URL)
+
+	_, err := c.GetInclusionProof(context.Background(), &api.GetInclusionProofRequest{Index: 1})
+	st.Refute(t, err, nil)
+}
+
 func TestClientAppend(t *testing.T) {
 	m := mockAppendServer{}
 	s := httptest.NewServer(&m)
@@ -217,8 +421,8 @@ func TestClientAppend(t *testing.T) {
 
 	seed := []byte("some seed")
 	res, err := c.AppendTransactions(context.Background(), &api.AppendRequest{
-		seed,
-		[]*api.UnsequencedTransaction{
+		NetworkSeed: seed,
+		Transactions: []*api.UnsequencedTransaction{
 			&api.UnsequencedTransaction{Data: []byte("some text")},
 			&api.UnsequencedTransaction{Data: []byte("some other text")},
 		},
@@ -230,6 +434,110 @@ func TestClientAppend(t *testing.T) {
 	st.Expect(t, m.lastSeed, hex.EncodeToString(seed))
 }
 
+func TestClientAppendReceipts(t *testing.T) {
+	l := mock.NewLedger()
+	s := httptest.NewServer(rest.NewServer(l).Router())
+	defer s.Close()
+	c := client.New(s.URL)
+
+	ctx := context.Background()
+	req := &api.AppendRequest{Transactions: utils.RandomUnsequencedTransactions(3, 100)}
+	res, err := c.AppendTransactions(ctx, req)
+	st.Assert(t, err, nil)
+	st.Assert(t, len(res.Receipts), 3)
+
+	read, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 3})
+	st.Assert(t, err, nil)
+	for i, r := range res.Receipts {
+		st.Expect(t, r.Hash, req.Transactions[i].Hash)
+		tx := read.Transactions[r.Index-1]
+		st.Expect(t, tx.Data, req.Transactions[i].Data)
+		st.Expect(t, r.StateHash, tx.StateHash)
+		st.Expect(t, r.Timestamp, tx.Timestamp)
+	}
+}
+
+func TestClientAppendDuplicate(t *testing.T) {
+	ctx := context.Background()
+	txs := utils.RandomUnsequencedTransactions(1, 100)
+
+	// Rejected duplicates
+	l := mock.NewLedger(mock.WithDedup(mock.DedupFullHistory))
+	s := httptest.NewServer(rest.NewServer(l).Router())
+	defer s.Close()
+	c := client.New(s.URL)
+	_, err := c.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	_, err = c.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	_, rejected := err.(api.BadRequestError)
+	st.Assert(t, rejected, true)
+
+	// Duplicates returning the original index
+	l = mock.NewLedger(mock.WithDedup(mock.DedupFullHistory), mock.WithDedupReturnIndex())
+	s2 := httptest.NewServer(rest.NewServer(l).Router())
+	defer s2.Close()
+	c = client.New(s2.URL)
+	_, err = c.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	res, err := c.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	st.Assert(t, len(res.Receipts), 1)
+	st.Expect(t, res.Receipts[0].Duplicate, true)
+	st.Expect(t, res.Receipts[0].Index, int64(1))
+}
+
+func TestClientAppendConflict(t *testing.T) {
+	l := mock.NewLedger()
+	s := httptest.NewServer(rest.NewServer(l).Router())
+	defer s.Close()
+	c := client.New(s.URL)
+
+	ctx := context.Background()
+	_, err := c.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions:      utils.RandomUnsequencedTransactions(2, 100),
+		ExpectedLastIndex: &api.ExpectedIndex{Index: 0},
+	})
+	st.Assert(t, err, nil)
+	_, err = c.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions:      utils.RandomUnsequencedTransactions(1, 100),
+		ExpectedLastIndex: &api.ExpectedIndex{Index: 0},
+	})
+	conflict, ok := err.(api.ConflictError)
+	st.Assert(t, ok, true)
+	st.Expect(t, conflict.LastIndex(), int64(2))
+}
+
+type mockBadReceiptServer struct{}
+
+func (m *mockBadReceiptServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add(rest.SymbiontNetworkSeedHeader, "736F6D652073656564")
+	fmt.Fprintf(w, `
+		{
+			"last_index":1,
+			"status":"sequenced",
+			"receipts":[
+				{
+					"tx_index":1,
+					"timestamp":1473418802676551328,
+					"hash":"b94f6f125c79e3a5ffaa826f584c10d52ada669e6762051b826b55776d05aed3",
+					"state_hash":"71a8e55edefe53f703646a679e66799cfef657b98474ff2e4148c3a1ea43169c"
+				}
+			]
+		}
+	`)
+}
+
+func TestClientAppendBadReceipt(t *testing.T) {
+	s := httptest.NewServer(&mockBadReceiptServer{})
+	defer s.Close()
+	c := client.New(s.URL)
+
+	_, err := c.AppendTransactions(context.Background(), &api.AppendRequest{
+		Transactions: []*api.UnsequencedTransaction{{Data: []byte("some text")}},
+	})
+	st.Refute(t, err, nil)
+}
+
 func TestClientAppendBadSeed(t *testing.T) {
 	m := mockAppendServer{}
 	s := httptest.NewServer(&m)
@@ -237,8 +545,8 @@ func TestClientAppendBadSeed(t *testing.T) {
 
 	seed := []byte("bad seed")
 	_, err := c.AppendTransactions(context.Background(), &api.AppendRequest{
-		seed,
-		[]*api.UnsequencedTransaction{
+		NetworkSeed: seed,
+		Transactions: []*api.UnsequencedTransaction{
 			&api.UnsequencedTransaction{Data: []byte("some text")},
 		},
 	})
@@ -247,3 +555,268 @@ func TestClientAppendBadSeed(t *testing.T) {
 	st.Assert(t, reject, true)
 	st.Expect(t, err.Error(), "seed mismatch")
 }
+
+func TestClientAppendAsync(t *testing.T) {
+	l := mock.NewLedger()
+	s := httptest.NewServer(rest.NewServer(l).Router())
+	defer s.Close()
+	c := client.New(s.URL, client.WithPollTimeout(50*time.Millisecond))
+
+	ctx := context.Background()
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(2, 100),
+	})
+	st.Assert(t, err, nil)
+
+	txs := utils.RandomUnsequencedTransactions(3, 100)
+	p, err := c.AppendTransactionsAsync(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	res, err := p.Wait(ctx)
+	st.Assert(t, err, nil)
+	st.Expect(t, res.LastIndex, int64(5))
+	st.Assert(t, len(res.Receipts), 3)
+	for i, r := range res.Receipts {
+		st.Expect(t, r.Hash, txs[i].Hash)
+		st.Expect(t, r.Index > 2, true)
+	}
+}
+
+// droppingLedger is a mock ledger that drops appended transactions of type
+// "drop".
+type droppingLedger struct {
+	*mock.Ledger
+}
+
+func (l droppingLedger) AppendTransactions(ctx context.Context, req *api.AppendRequest) (*api.AppendResult, error) {
+	var txs []*api.UnsequencedTransaction
+	for _, tx := range req.Transactions {
+		if tx.Type != "drop" {
+			txs = append(txs, tx)
+		}
+	}
+	return l.Ledger.AppendTransactions(ctx, &api.AppendRequest{
+		NetworkSeed:  req.NetworkSeed,
+		Transactions: txs,
+	})
+}
+
+func TestClientAppendAsyncConditional(t *testing.T) {
+	l := mock.NewLedger()
+	s := httptest.NewServer(rest.NewServer(l).Router())
+	defer s.Close()
+	c := client.New(s.URL)
+
+	_, err := c.AppendTransactionsAsync(context.Background(), &api.AppendRequest{
+		Transactions:      utils.RandomUnsequencedTransactions(1, 100),
+		ExpectedLastIndex: &api.ExpectedIndex{Index: 0},
+	})
+	_, ok := err.(api.BadRequestError)
+	st.Expect(t, ok, true)
+}
+
+func TestClientAppendAsyncTimeout(t *testing.T) {
+	l := droppingLedger{mock.NewLedger()}
+	s := httptest.NewServer(rest.NewServer(l).Router())
+	defer s.Close()
+	c := client.New(s.URL, client.WithSequencingTimeout(100*time.Millisecond))
+
+	txs := []*api.UnsequencedTransaction{
+		{Type: "keep", Data: []byte("1")},
+		{Type: "drop", Data: []byte("2")},
+		{Type: "keep", Data: []byte("3")},
+	}
+	p, err := c.AppendTransactionsAsync(context.Background(), &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	select {
+	case <-p.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for append to resolve")
+	}
+	_, err = p.Wait(context.Background())
+	e, ok := err.(client.NotSequencedError)
+	st.Assert(t, ok, true)
+	st.Assert(t, len(e.Transactions), 1)
+	st.Expect(t, e.Transactions[0].Data, []byte("2"))
+	st.Expect(t, e.Result.Receipts[1], (*api.Receipt)(nil))
+	st.Refute(t, e.Result.Receipts[2], nil)
+}
+
+func TestClientAppendAsyncCancel(t *testing.T) {
+	l := droppingLedger{mock.NewLedger()}
+	s := httptest.NewServer(rest.NewServer(l).Router())
+	defer s.Close()
+	c := client.New(s.URL, client.WithSequencingTimeout(time.Minute))
+
+	// Tracking stops when the caller's context is cancelled.
+	ctx, cancel := context.WithCancel(context.Background())
+	p, err := c.AppendTransactionsAsync(ctx, &api.AppendRequest{
+		Transactions: []*api.UnsequencedTransaction{{Type: "drop", Data: []byte("1")}},
+	})
+	st.Assert(t, err, nil)
+	cancel()
+	select {
+	case <-p.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for append to resolve")
+	}
+	_, err = p.Wait(context.Background())
+	st.Expect(t, err, context.Canceled)
+}
+
+// oldServer mimics a server from before protobuf and gzip support, only
+// exchanging uncompressed JSON.
+type oldServer struct {
+	handler  http.Handler
+	rejected int
+}
+
+func (s *oldServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(rest.VersionHeader, "1.0.0")
+	if rest.IsProtobuf(r.Header) || r.Header.Get("Content-Encoding") != "" {
+		s.rejected++
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"Failed to parse body"}`)
+		return
+	}
+	r.Header.Del("Accept")
+	r.Header.Del("Accept-Encoding")
+	s.handler.ServeHTTP(w, r)
+}
+
+func TestClientProtobuf(t *testing.T) {
+	l := mock.NewLedger()
+	var contentTypes []string
+	h := rest.NewServer(l).Router()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+		h.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		c           *client.Client
+		contentType string
+	}{
+		{client.New(s.URL), rest.ProtobufContentType},
+		{client.New(s.URL, client.WithProtobuf(false)), "application/json"},
+	} {
+		c := tc.c
+		contentTypes = nil
+		status, err := c.ServerStatus(ctx, nil)
+		st.Assert(t, err, nil)
+		txs := utils.RandomUnsequencedTransactions(2, 100)
+		res, err := c.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+		st.Assert(t, err, nil)
+		st.Expect(t, res.NetworkSeed, status.NetworkSeed)
+		st.Assert(t, len(res.Receipts), 2)
+		read, err := c.ReadTransactions(ctx, &api.ReadRequest{Index: res.Receipts[0].Index})
+		st.Assert(t, err, nil)
+		st.Assert(t, len(read.Transactions), 2)
+		st.Expect(t, read.Transactions[1].Data, txs[1].Data)
+		st.Expect(t, read.LastIndex, res.LastIndex)
+		st.Assert(t, len(contentTypes), 3)
+		st.Expect(t, contentTypes[1], tc.contentType)
+	}
+
+	// Servers not supporting protobuf are talked to in JSON.
+	old := &oldServer{handler: h}
+	s2 := httptest.NewServer(old)
+	defer s2.Close()
+	c := client.New(s2.URL, client.WithCompressThreshold(0))
+	_, err := c.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+	res, err := c.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, old.rejected, 1)
+	read, err := c.ReadTransactions(ctx, &api.ReadRequest{Index: res.LastIndex})
+	st.Assert(t, err, nil)
+	st.Expect(t, len(read.Transactions), 1)
+}
+
+func TestClientCompression(t *testing.T) {
+	l := mock.NewLedger()
+	var encodings []string
+	h := rest.NewServer(l).Router()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		h.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	ctx := context.Background()
+	c := client.New(s.URL, client.WithCompressThreshold(1000))
+	txs := utils.RandomUnsequencedTransactions(1, 100)
+	_, err := c.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	txs = append(txs, utils.RandomUnsequencedTransactions(10, 100)...)
+	res, err := c.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	st.Expect(t, encodings, []string{"", "gzip"})
+	st.Expect(t, len(res.Receipts), 11)
+
+	// Responses are decompressed.
+	read, err := c.ReadTransactions(ctx, &api.ReadRequest{Index: 1})
+	st.Assert(t, err, nil)
+	st.Assert(t, len(read.Transactions), 12)
+	st.Expect(t, read.Transactions[11].Data, txs[10].Data)
+}
+
+func TestClientExport(t *testing.T) {
+	l := mock.NewLedger()
+	s := httptest.NewServer(rest.NewServer(l, rest.WithMaxCount(10)).Router())
+	defer s.Close()
+	ctx := context.Background()
+	txs := utils.RandomUnsequencedTransactions(25, 100)
+	res, err := l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+
+	c := client.New(s.URL)
+	var exported []*api.SequencedTransaction
+	last, err := c.Export(ctx, res.NetworkSeed, 3, 0, func(tx *api.SequencedTransaction) error {
+		exported = append(exported, tx)
+		return nil
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, last, int64(25))
+	st.Assert(t, len(exported), 23)
+	st.Expect(t, exported[0].Index, int64(3))
+	st.Expect(t, exported[22].Data, txs[24].Data)
+
+	// Errors from the callback stop the export.
+	last, err = c.Export(ctx, nil, 1, 20, func(tx *api.SequencedTransaction) error {
+		if tx.Index == 12 {
+			return fmt.Errorf("stop")
+		}
+		return nil
+	})
+	st.Expect(t, err, fmt.Errorf("stop"))
+	st.Expect(t, last, int64(11))
+
+	_, err = c.Export(ctx, []byte("other seed"), 1, 0, func(*api.SequencedTransaction) error {
+		return nil
+	})
+	_, ok := err.(api.NetworkSeedMismatchError)
+	st.Expect(t, ok, true)
+}
+
+func TestClientExportBadStateHash(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(rest.SymbiontLastIndexHeader, "2")
+		for _, tx := range rest.EncodeSequencedTransactions([]*api.SequencedTransaction{
+			utils.MockSequencedTransaction(1),
+			utils.MockSequencedTransaction(2),
+		}) {
+			json.NewEncoder(w).Encode(tx)
+		}
+	}))
+	defer s.Close()
+
+	c := client.New(s.URL)
+	_, err := c.Export(context.Background(), nil, 1, 0, func(*api.SequencedTransaction) error {
+		return nil
+	})
+	st.Refute(t, err, nil)
+}
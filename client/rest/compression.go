@@ -0,0 +1,86 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/symbiont-io/assembly-sdk/api/rest"
+)
+
+// Versions of the API from which servers accept protobuf and gzip encoded
+// requests respectively.
+const (
+	protobufVersion = "1.1.0"
+	gzipVersion     = "1.2.0"
+)
+
+// serverSupports returns true if the server that sent the response headers has
+// an API version of at least the provided one.
+func serverSupports(h http.Header, version string) bool {
+	have := strings.Split(h.Get(rest.VersionHeader), ".")
+	want := strings.Split(version, ".")
+	for i, w := range want {
+		if i >= len(have) {
+			return false
+		}
+		a, err := strconv.Atoi(have[i])
+		if err != nil {
+			return false
+		}
+		b, _ := strconv.Atoi(w)
+		if a != b {
+			return a > b
+		}
+	}
+	return true
+}
+
+// gzipBody is a gzip encoded response body being decompressed.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// do sends the request, asking for a gzip encoded response, and returns the
+// response with its body decompressed.
+func (c *Client) do(r *http.Request) (*http.Response, error) {
+	r.Header.Set("Accept-Encoding", "gzip")
+	client := &http.Client{}
+	resp, err := client.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("Failed to decompress response: %v", err)
+		}
+		resp.Body = gzipBody{gz, resp.Body}
+		resp.Header.Del("Content-Encoding")
+	}
+	return resp, nil
+}
+
+// gzipData compresses data with gzip.
+func gzipData(data []byte) ([]byte, error) {
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	if _, err := gz.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/api/rest"
+)
+
+// Export streams the transactions with indexes from `from` up to `to` from the
+// ledger in a single request, calling fn with each of them in order. If to is
+// 0, the export runs up to the ledger's last index at the time of the request.
+// This is much faster than reading a large ledger page by page, and uses
+// constant memory.
+//
+// Transactions are verified as they're received: their hashes must match
+// their contents, indexes must follow each other and state hashes must chain
+// from the one before. If from is larger than 1, the state hash of the
+// preceding transaction is read from the ledger first. The export stops at the
+// first error, including errors returned by fn. If a network seed is provided,
+// it will be checked against the ledger's, and an error returned in case of a
+// mismatch. The index of the last transaction exported is returned.
+func (c *Client) Export(ctx context.Context, seed []byte, from, to int64, fn func(*api.SequencedTransaction) error) (int64, error) {
+	// Find the state hash the export has to chain from.
+	var stateHash []byte
+	if from > 1 {
+		res, err := c.ReadTransactions(ctx, &api.ReadRequest{
+			NetworkSeed: seed,
+			Index:       from - 1,
+			Count:       1,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("Failed to read transaction %d: %v", from-1, err)
+		}
+		if len(res.Transactions) == 0 {
+			return 0, fmt.Errorf("Failed to read transaction %d", from-1)
+		}
+		stateHash = res.Transactions[0].StateHash
+	}
+
+	u, err := url.Parse(c.host)
+	if err != nil {
+		c.fatalf("Failed to parse host %q: %v", c.host, err)
+	}
+	u.Path += "/export"
+	q := url.Values{"from": {strconv.FormatInt(from, 10)}}
+	if to != 0 {
+		q.Set("to", strconv.FormatInt(to, 10))
+	}
+	u.RawQuery = q.Encode()
+
+	// Perform GET request. No default timeout is set, since exports can take
+	// a long time.
+	r, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to create GET request to %q: %v", c.host, err)
+	}
+	r = r.WithContext(ctx)
+	r.Header.Add(rest.SymbiontNetworkSeedHeader, hex.EncodeToString(seed))
+
+	resp, err := c.do(r)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to send GET request to %q: %v", c.host, err)
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		var res rest.TransactionResult
+		if err := dec.Decode(&res); err != nil {
+			return 0, fmt.Errorf("Failed to decode response: %v", err)
+		}
+		seed, _ := decodeAndVerifyNetworkSeed(resp.Header, nil)
+		return 0, newError(resp.StatusCode, res.Error, seed)
+	}
+	if _, err := decodeAndVerifyNetworkSeed(resp.Header, seed); err != nil {
+		return 0, err
+	}
+	last, err := strconv.ParseInt(resp.Header.Get(rest.SymbiontLastIndexHeader), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to parse last index in response: %v", err)
+	}
+
+	// Decode and verify transactions line by line.
+	index := from
+	for {
+		var res rest.TransactionResult
+		err := dec.Decode(&res)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return index - 1, fmt.Errorf("Failed to decode transaction %d: %v", index, err)
+		}
+		if res.Error != "" {
+			return index - 1, fmt.Errorf("Export failed at index %d: %s", index, res.Error)
+		}
+		if res.EncodedSequencedTransaction == nil {
+			return index - 1, fmt.Errorf("No transaction at index %d", index)
+		}
+		txs, err := rest.DecodeSequencedTransactions(
+			[]*rest.EncodedSequencedTransaction{res.EncodedSequencedTransaction})
+		if err != nil {
+			return index - 1, fmt.Errorf("Failed to decode transaction %d: %v", index, err)
+		}
+		tx := txs[0]
+		if tx.Index != index {
+			return index - 1, fmt.Errorf("Unexpected index of tx (got %d, expected %d)", tx.Index, index)
+		}
+		h := sha256.Sum256(append(append([]byte{}, stateHash...), tx.Hash...))
+		if !bytes.Equal(tx.StateHash, h[:]) {
+			return index - 1, fmt.Errorf("State hash mismatch on transaction %d", index)
+		}
+		if err := fn(tx); err != nil {
+			return index - 1, err
+		}
+		stateHash = tx.StateHash
+		index++
+	}
+	if index-1 != last {
+		return index - 1, fmt.Errorf("Export ended at index %d, expected %d", index-1, last)
+	}
+	return last, nil
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"golang.org/x/crypto/ed25519"
 	"time"
 )
 
@@ -27,15 +28,36 @@ type options struct {
 	// completing an append request.
 	appendTimeout time.Duration
 
+	// sequencingTimeout is the maximum duration the client waits for
+	// asynchronously appended transactions to show up on the ledger.
+	sequencingTimeout time.Duration
+
+	// trustedKey is the public key of the ledger node, which statuses and
+	// append receipts must be signed with. Signatures aren't checked if unset.
+	trustedKey ed25519.PublicKey
+
+	// protobuf tells whether to exchange protobuf encoded messages with the
+	// server on reads, appends and status requests, rather than JSON. Servers
+	// not supporting it are still talked to in JSON.
+	protobuf bool
+
+	// compressThreshold is the size in bytes from which encoded append
+	// requests are compressed with gzip. Requests are never compressed if
+	// it's negative.
+	compressThreshold int
+
 	// logger is the logger used by the client.
 	logger Logger
 }
 
 var defaultOptions = options{
-	maxCount:      100,
-	pollTimeout:   10 * time.Second,
-	appendTimeout: 10 * time.Second,
-	callTimeout:   2 * time.Second,
+	maxCount:          100,
+	pollTimeout:       10 * time.Second,
+	appendTimeout:     10 * time.Second,
+	callTimeout:       2 * time.Second,
+	sequencingTimeout: time.Minute,
+	protobuf:          true,
+	compressThreshold: 16 * 1024,
 }
 
 type Option func(*options)
@@ -68,6 +90,38 @@ func WithCallTimeout(t time.Duration) Option {
 	}
 }
 
+// WithSequencingTimeout changes sequencingTimeout from the default value.
+func WithSequencingTimeout(t time.Duration) Option {
+	return func(o *options) {
+		o.sequencingTimeout = t
+	}
+}
+
+// WithTrustedKey makes the client verify that statuses and append receipts
+// are signed with the private key matching the provided Ed25519 public key,
+// rejecting unsigned or badly signed responses. Receipts of asynchronous
+// appends are built from reads and aren't signed.
+func WithTrustedKey(key ed25519.PublicKey) Option {
+	return func(o *options) {
+		o.trustedKey = key
+	}
+}
+
+// WithProtobuf changes protobuf from the default value. Disabling it makes the
+// client only exchange JSON, which is easier to debug.
+func WithProtobuf(enabled bool) Option {
+	return func(o *options) {
+		o.protobuf = enabled
+	}
+}
+
+// WithCompressThreshold changes compressThreshold from the default value.
+func WithCompressThreshold(n int) Option {
+	return func(o *options) {
+		o.compressThreshold = n
+	}
+}
+
 // WithLogger sets a logger.
 func WithLogger(l Logger) Option {
 	return func(o *options) {
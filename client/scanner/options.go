@@ -1,6 +1,10 @@
 package scanner
 
-import "time"
+import (
+	"time"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
 
 // options holds the configurable options of a scanner. It is not meant to be
 // used directly; the scanner initializes it with default values that are then
@@ -10,6 +14,7 @@ type options struct {
 	transactionType string
 	retries         int
 	retryPeriod     time.Duration
+	verifier        Verifier
 
 	// logger is the logger used by the scanner.
 	logger Logger
@@ -21,7 +26,10 @@ var defaultOptions = options{
 
 type Option func(*options)
 
-// WithTypeFilter sets a filter on the transaction type, only returning matching transactions.
+// WithTypeFilter sets a filter on the transaction type, only returning matching
+// transactions. A type ending in "*" matches any type with the preceding
+// prefix. The filter is passed on to the ledger, so that non-matching
+// transactions needn't be transferred, and applied again by the scanner.
 func WithTypeFilter(t string) Option {
 	return func(o *options) {
 		o.filter = true
@@ -39,6 +47,19 @@ func WithRetries(count int) Option {
 	}
 }
 
+// Verifier checks a transaction read by the scanner, returning an error if it
+// shouldn't be trusted.
+type Verifier func(*api.SequencedTransaction) error
+
+// WithVerifier sets a function to check transactions with before they're
+// output, eg. one returned by envelope.Verifier. The scan stops with the
+// returned error at the first transaction failing the check.
+func WithVerifier(v Verifier) Option {
+	return func(o *options) {
+		o.verifier = v
+	}
+}
+
 // WithLogger sets a logger.
 func WithLogger(l Logger) Option {
 	return func(o *options) {
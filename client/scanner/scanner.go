@@ -48,10 +48,14 @@ func (s *Scanner) Scan(index int64, seed []byte) <-chan *api.SequencedTransactio
 	go func() {
 		retried := 0
 		for {
-			res, err := s.client.ReadTransactions(context.Background(), &api.ReadRequest{
+			req := api.ReadRequest{
 				NetworkSeed: seed,
 				Index:       index,
-			})
+			}
+			if s.options.filter {
+				req.Types = []string{s.options.transactionType}
+			}
+			res, err := s.client.ReadTransactions(context.Background(), &req)
 			if err != nil {
 				if s.options.retries > retried || s.options.retries == InfiniteRetries {
 					retried++
@@ -65,15 +69,28 @@ func (s *Scanner) Scan(index int64, seed []byte) <-chan *api.SequencedTransactio
 			}
 			retried = 0
 			for _, tx := range res.Transactions {
-				if tx.Index != index {
+				// Filtered reads skip non-matching transactions.
+				if tx.Index != index && !(s.options.filter && tx.Index > index) {
 					s.err = fmt.Errorf("Unexpected transaction index (expected %d, got %d)",
 						index, tx.Index)
 					break
 				}
-				if !s.options.filter || tx.Type == s.options.transactionType {
+				if !s.options.filter || api.MatchesType(tx.Type, req.Types) {
+					if s.options.verifier != nil {
+						if err := s.options.verifier(tx); err != nil {
+							s.err = err
+							break
+						}
+					}
 					results <- tx
 				}
-				index++
+				index = tx.Index + 1
+			}
+			if s.err != nil {
+				break
+			}
+			if res.LastIndex >= index {
+				index = res.LastIndex + 1
 			}
 		}
 		close(results)
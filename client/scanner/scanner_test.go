@@ -192,3 +192,71 @@ func TestScannerWithFilter(t *testing.T) {
 	st.Expect(t, count, 2)
 	st.Expect(t, s.Error(), errors.New("done"))
 }
+
+// recordingClient is a mockClient keeping the requests made through it.
+type recordingClient struct {
+	mockClient
+	requests []api.ReadRequest
+}
+
+func (rc *recordingClient) ReadTransactions(ctx context.Context, req *api.ReadRequest) (*api.ReadResult, error) {
+	rc.requests = append(rc.requests, *req)
+	return rc.mockClient.ReadTransactions(ctx, req)
+}
+
+func TestScannerWithServerFilter(t *testing.T) {
+	c := &recordingClient{mockClient: mockClient{
+		[]*api.ReadResult{
+			&api.ReadResult{
+				Transactions: []*api.SequencedTransaction{
+					utils.MockTypedSequencedTransaction("a/x", 2),
+					utils.MockTypedSequencedTransaction("a/y", 5),
+				},
+				LastIndex: 7,
+			},
+			&api.ReadResult{
+				LastIndex: 10,
+			},
+		},
+	}}
+	s := scanner.New(c, scanner.WithTypeFilter("a/*"))
+	txs := s.Scan(1, nil)
+	var indexes []int64
+	for tx := range txs {
+		indexes = append(indexes, tx.Index)
+	}
+	st.Expect(t, indexes, []int64{2, 5})
+	st.Expect(t, s.Error(), errors.New("done"))
+
+	// Reads continue after the last index scanned by the server.
+	st.Assert(t, len(c.requests), 3)
+	st.Expect(t, c.requests[0].Types, []string{"a/*"})
+	st.Expect(t, c.requests[1].Index, int64(8))
+	st.Expect(t, c.requests[2].Index, int64(11))
+}
+
+func TestScannerWithVerifier(t *testing.T) {
+	s := scanner.New(&mockClient{
+		[]*api.ReadResult{
+			&api.ReadResult{
+				Transactions: []*api.SequencedTransaction{
+					utils.MockSequencedTransaction(1),
+					utils.MockSequencedTransaction(2),
+					utils.MockSequencedTransaction(3),
+				},
+			},
+		},
+	}, scanner.WithVerifier(func(tx *api.SequencedTransaction) error {
+		if tx.Index == 2 {
+			return errors.New("bad signature")
+		}
+		return nil
+	}))
+	txs := s.Scan(1, nil)
+	var indexes []int64
+	for tx := range txs {
+		indexes = append(indexes, tx.Index)
+	}
+	st.Expect(t, indexes, []int64{1})
+	st.Expect(t, s.Error(), errors.New("bad signature"))
+}
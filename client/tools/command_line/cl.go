@@ -43,7 +43,7 @@ func main() {
 				})
 			}
 			before := time.Now()
-			res, err := c.AppendTransactions(context.Background(), &api.AppendRequest{status.NetworkSeed, txs})
+			res, err := c.AppendTransactions(context.Background(), &api.AppendRequest{NetworkSeed: status.NetworkSeed, Transactions: txs})
 			if err != nil {
 				return err
 			}
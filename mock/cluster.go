@@ -0,0 +1,649 @@
+package mock
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/api/merkle"
+	"github.com/symbiont-io/assembly-sdk/api/rest"
+)
+
+// Cluster simulates a ledger of several nodes in a single process, to test how
+// clients cope with nodes that lag behind, are cut off from the rest of the
+// network or go down. The nodes share a single Ledger, which sequences the
+// transactions appended to any of them, but each node only sees the
+// transactions it has replicated. Each node is served on its own rest.Server,
+// listening on a local port. Transactions must be appended through the nodes,
+// and the ledger reset through the cluster, rather than directly on the shared
+// ledger, for the nodes to see it.
+type Cluster struct {
+	ledger *Ledger
+	nodes  []*Node
+
+	mu sync.Mutex
+	// commits records when the shared ledger reached each last index, in
+	// order, so that nodes can tell which transactions they have replicated.
+	commits []commit
+	// changed is closed and replaced whenever transactions are appended or
+	// the state of a node changes, waking readers waiting on the nodes.
+	changed chan struct{}
+	// resets counts the resets of the shared ledger, so that appends racing
+	// with a reset aren't recorded as commits of the new log.
+	resets int
+}
+
+// commit records the time an append brought the shared ledger to lastIndex.
+type commit struct {
+	time      time.Time
+	lastIndex int64
+}
+
+// Node is a node of a Cluster. It implements api.LedgerServer, serving the
+// transactions it has replicated from the shared ledger and forwarding appends
+// to it, as long as it's up and connected to the rest of the cluster.
+type Node struct {
+	cluster *Cluster
+	addr    string
+	server  *http.Server
+	conns   *connTracker
+
+	// The fields below are protected by the cluster's mutex.
+
+	// lag is how long after being sequenced a transaction is replicated to
+	// the node.
+	lag time.Duration
+	// partitioned and down tell whether the node is cut off from the rest of
+	// the cluster, or crashed.
+	partitioned, down bool
+	// base is the last index the node held when it was last partitioned or
+	// crashed, and resumed the time it was last healed or restarted.
+	base    int64
+	resumed time.Time
+}
+
+// NewCluster creates a cluster of n nodes sharing a new Ledger, created with
+// the provided options, and starts serving each node on a local port. All
+// nodes start up to date, without lag.
+func NewCluster(n int, opt ...Option) (*Cluster, error) {
+	c := &Cluster{
+		ledger:  NewLedger(opt...),
+		changed: make(chan struct{}),
+	}
+	now := c.ledger.options.clock.Now()
+	for i := 0; i < n; i++ {
+		node := &Node{cluster: c, resumed: now}
+		if err := node.serve("localhost:0"); err != nil {
+			c.Close()
+			return nil, err
+		}
+		c.nodes = append(c.nodes, node)
+	}
+	return c, nil
+}
+
+// Ledger returns the ledger shared by the nodes of the cluster.
+func (c *Cluster) Ledger() *Ledger {
+	return c.ledger
+}
+
+// Nodes returns the nodes of the cluster.
+func (c *Cluster) Nodes() []*Node {
+	return c.nodes
+}
+
+// URLs returns the base URLs of the REST APIs of the nodes of the cluster.
+func (c *Cluster) URLs() []string {
+	urls := make([]string, 0, len(c.nodes))
+	for _, n := range c.nodes {
+		urls = append(urls, n.URL())
+	}
+	return urls
+}
+
+// Close stops serving the nodes of the cluster and closes the shared ledger.
+func (c *Cluster) Close() error {
+	for _, n := range c.nodes {
+		c.mu.Lock()
+		if !n.down {
+			n.stop()
+		}
+		c.mu.Unlock()
+	}
+	return c.ledger.Close()
+}
+
+// Reset resets the shared ledger, as Ledger.Reset does, and empties the nodes,
+// which start over with the new log. Nodes that are up and connected are up to
+// date straight away; the others catch up after their lag once they're healed
+// or restarted.
+func (c *Cluster) Reset() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	seed, err := c.ledger.Reset()
+	if err != nil {
+		return nil, err
+	}
+	c.commits = nil
+	c.resets++
+	now := c.ledger.options.clock.Now()
+	for _, n := range c.nodes {
+		n.base = 0
+		if !n.down && !n.partitioned {
+			n.resumed = now.Add(-n.lag)
+		}
+	}
+	c.notify()
+	return seed, nil
+}
+
+// notify wakes readers waiting on the nodes. The caller must hold the
+// cluster's mutex.
+func (c *Cluster) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// committedAt returns the last index of the shared ledger at time t, as far as
+// the cluster knows. The caller must hold the cluster's mutex.
+func (c *Cluster) committedAt(t time.Time) int64 {
+	i := sort.Search(len(c.commits), func(i int) bool {
+		return c.commits[i].time.After(t)
+	})
+	if i == 0 {
+		return 0
+	}
+	return c.commits[i-1].lastIndex
+}
+
+// serve starts serving the node's REST API on addr.
+func (n *Node) serve(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("Failed to listen on %q: %v", addr, err)
+	}
+	n.addr = lis.Addr().String()
+	n.conns = &connTracker{listener: lis, conns: make(map[net.Conn]struct{})}
+	n.server = &http.Server{
+		Handler:   rest.NewServer(n).Router(),
+		ConnState: n.conns.track,
+	}
+	go n.server.Serve(lis)
+	return nil
+}
+
+// stop stops serving the node's REST API, closing its listener and any open
+// connections.
+func (n *Node) stop() {
+	n.conns.close()
+}
+
+// connTracker keeps track of the connections of an http.Server, so that they
+// can be closed along with its listener.
+type connTracker struct {
+	listener net.Listener
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+}
+
+// track records the state changes of connections. It's set as the ConnState
+// hook of the server.
+func (t *connTracker) track(c net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch state {
+	case http.StateNew:
+		if t.closed {
+			// Accepted just as the server was stopped.
+			c.Close()
+			return
+		}
+		t.conns[c] = struct{}{}
+	case http.StateHijacked, http.StateClosed:
+		delete(t.conns, c)
+	}
+}
+
+// close closes the listener and all open connections.
+func (t *connTracker) close() {
+	t.listener.Close()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	for c := range t.conns {
+		c.Close()
+	}
+	t.conns = nil
+}
+
+// URL returns the base URL of the node's REST API.
+func (n *Node) URL() string {
+	return "http://" + n.addr
+}
+
+// SetLag sets how long after being sequenced transactions are replicated to
+// the node. Transactions already replicated stay on the node, and a node
+// that's up to date stays ready.
+func (n *Node) SetLag(lag time.Duration) {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+	n.base = n.lastIndex()
+	if n.ready() {
+		// Treat the node as connected for long enough to not be catching up.
+		if since := n.cluster.ledger.options.clock.Now().Add(-lag); since.Before(n.resumed) {
+			n.resumed = since
+		}
+	}
+	n.lag = lag
+	n.cluster.notify()
+}
+
+// Reset resets the shared ledger through the cluster, so that the REST API of
+// the node can serve admin resets.
+func (n *Node) Reset() ([]byte, error) {
+	return n.cluster.Reset()
+}
+
+// Partition cuts the node off from the rest of the cluster. It keeps serving
+// the transactions it has replicated, but doesn't replicate new ones, fails
+// appends and reports itself as not ready until it's healed.
+func (n *Node) Partition() {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+	n.base = n.lastIndex()
+	n.partitioned = true
+	n.cluster.notify()
+}
+
+// Heal reconnects a partitioned node to the rest of the cluster. It catches up
+// with the transactions sequenced while it was partitioned after its lag.
+func (n *Node) Heal() {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+	n.partitioned = false
+	n.resumed = n.cluster.ledger.options.clock.Now()
+	n.cluster.notify()
+}
+
+// Crash stops the node, closing its REST API and any open connections.
+// Requests made directly to the node fail with a ServerError until it's
+// restarted.
+func (n *Node) Crash() {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+	if n.down {
+		return
+	}
+	n.base = n.lastIndex()
+	n.down = true
+	n.stop()
+	n.cluster.notify()
+}
+
+// Restart restarts a crashed node, serving its REST API on the same address
+// as before. The node keeps the transactions it had replicated before it
+// crashed, and catches up with the others after its lag.
+func (n *Node) Restart() error {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+	if !n.down {
+		return nil
+	}
+	if err := n.serve(n.addr); err != nil {
+		return err
+	}
+	n.down = false
+	n.resumed = n.cluster.ledger.options.clock.Now()
+	n.cluster.notify()
+	return nil
+}
+
+// lastIndex returns the index of the last transaction replicated to the node.
+// A node replicates the transactions sequenced up to lag ago, but only once
+// it has been up and connected for lag. The caller must hold the cluster's
+// mutex.
+func (n *Node) lastIndex() int64 {
+	if n.down || n.partitioned {
+		return n.base
+	}
+	now := n.cluster.ledger.options.clock.Now()
+	if now.Before(n.resumed.Add(n.lag)) {
+		return n.base
+	}
+	if last := n.cluster.committedAt(now.Add(-n.lag)); last > n.base {
+		return last
+	}
+	return n.base
+}
+
+// ready tells whether the node is up to date, ie. connected and not catching
+// up after being healed or restarted. The caller must hold the cluster's
+// mutex.
+func (n *Node) ready() bool {
+	now := n.cluster.ledger.options.clock.Now()
+	return !n.down && !n.partitioned && !now.Before(n.resumed.Add(n.lag))
+}
+
+// checkSeed returns the network seed of the shared ledger, or a
+// NetworkSeedMismatchError if the provided seed is set and doesn't match it.
+func (n *Node) checkSeed(seed []byte) ([]byte, error) {
+	l := n.cluster.ledger
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.verifySeed(seed) {
+		return nil, api.NetworkSeedMismatchError(l.store.Seed())
+	}
+	return l.store.Seed(), nil
+}
+
+// held returns the index of the last transaction replicated to the node, or
+// an error if the node is down.
+func (n *Node) held() (int64, error) {
+	n.cluster.mu.Lock()
+	defer n.cluster.mu.Unlock()
+	if n.down {
+		return 0, api.ServerError("Node is down")
+	}
+	return n.lastIndex(), nil
+}
+
+// waitFor blocks until the node has replicated the transaction at index, the
+// node goes down or the context is done.
+func (n *Node) waitFor(ctx context.Context, index int64) {
+	clock := n.cluster.ledger.options.clock
+	for {
+		n.cluster.mu.Lock()
+		if n.down || n.lastIndex() >= index {
+			n.cluster.mu.Unlock()
+			return
+		}
+		changed := n.cluster.changed
+		// If the transaction has been sequenced, wake up when it should be
+		// replicated.
+		var replicated <-chan time.Time
+		if !n.partitioned {
+			i := sort.Search(len(n.cluster.commits), func(i int) bool {
+				return n.cluster.commits[i].lastIndex >= index
+			})
+			if i < len(n.cluster.commits) {
+				at := n.cluster.commits[i].time.Add(n.lag)
+				if resumed := n.resumed.Add(n.lag); at.Before(resumed) {
+					at = resumed
+				}
+				replicated = clock.After(at.Sub(clock.Now()))
+			}
+		}
+		n.cluster.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-replicated:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// read reads up to count transactions matching the types from the shared
+// ledger, starting at index and stopping at the last index held by the node.
+// It returns the transactions and the index of the last transaction scanned.
+func (n *Node) read(ctx context.Context, seed []byte, index, count, held int64, types []string) ([]*api.SequencedTransaction, int64, error) {
+	var txs []*api.SequencedTransaction
+	last := index - 1
+	for int64(len(txs)) < count && last < held {
+		batch := held - last
+		if batch > scanBatchSize {
+			batch = scanBatchSize
+		}
+		res, err := n.cluster.ledger.ReadTransactions(ctx, &api.ReadRequest{
+			NetworkSeed: seed,
+			Index:       last + 1,
+			Count:       batch,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, tx := range res.Transactions {
+			if api.MatchesType(tx.Type, types) {
+				txs = append(txs, tx)
+			}
+			last = tx.Index
+			if int64(len(txs)) == count {
+				break
+			}
+		}
+	}
+	return txs, last, nil
+}
+
+// ReadTransactions reads the transactions replicated to the node, waiting for
+// new ones to be replicated as Ledger.ReadTransactions waits for new ones to
+// be appended.
+func (n *Node) ReadTransactions(ctx context.Context, req *api.ReadRequest) (*api.ReadResult, error) {
+	held, err := n.held()
+	if err != nil {
+		return nil, err
+	}
+	seed, err := n.checkSeed(req.NetworkSeed)
+	if err != nil {
+		return nil, err
+	}
+	if req.Index > held+1 {
+		return nil, api.NotFoundError("Requested index is too far in the future")
+	} else if req.Index == held+1 {
+		n.waitFor(ctx, req.Index)
+		if held, err = n.held(); err != nil {
+			return nil, err
+		}
+	}
+	txs, last, err := n.read(ctx, req.NetworkSeed, req.Index, req.Count, held, req.Types)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ReadResult{
+		NetworkSeed:  seed,
+		Transactions: txs,
+		LastIndex:    last,
+	}, nil
+}
+
+// AppendTransactions forwards the append to the shared ledger, unless the
+// node is down or partitioned. The transactions are only replicated to the
+// node, and readable from it, after its lag.
+func (n *Node) AppendTransactions(ctx context.Context, req *api.AppendRequest) (*api.AppendResult, error) {
+	n.cluster.mu.Lock()
+	down, partitioned, resets := n.down, n.partitioned, n.cluster.resets
+	n.cluster.mu.Unlock()
+	if down {
+		return nil, api.ServerError("Node is down")
+	}
+	if partitioned {
+		return nil, api.ServerError("Node is partitioned from the cluster")
+	}
+
+	res, err := n.cluster.ledger.AppendTransactions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	c := n.cluster
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.resets != resets {
+		// The ledger was reset, so the transactions are gone.
+		return res, nil
+	}
+	if len(c.commits) == 0 || res.LastIndex > c.commits[len(c.commits)-1].lastIndex {
+		now := c.ledger.options.clock.Now()
+		if len(c.commits) > 0 && now.Before(c.commits[len(c.commits)-1].time) {
+			// Keep commits in order if the clock steps back.
+			now = c.commits[len(c.commits)-1].time
+		}
+		c.commits = append(c.commits, commit{now, res.LastIndex})
+		c.notify()
+	}
+	return res, nil
+}
+
+// ServerStatus returns the status of the shared ledger as seen by the node:
+// with the last index, state hash and Merkle root of the transactions it has
+// replicated. The node isn't ready while partitioned, or while catching up
+// after being healed or restarted.
+func (n *Node) ServerStatus(ctx context.Context, e *api.Empty) (*api.ServerStatusResult, error) {
+	n.cluster.mu.Lock()
+	held, ready, down := n.lastIndex(), n.ready(), n.down
+	n.cluster.mu.Unlock()
+	if down {
+		return nil, api.ServerError("Node is down")
+	}
+
+	status, err := n.cluster.ledger.ServerStatus(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := n.cluster.ledger.GetConsistencyProof(ctx, &api.GetConsistencyProofRequest{
+		From: held,
+		To:   status.LastIndex,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var stateHash []byte
+	if held > 0 {
+		res, err := n.cluster.ledger.ReadTransactions(ctx, &api.ReadRequest{Index: held, Count: 1})
+		if err != nil {
+			return nil, err
+		}
+		stateHash = res.Transactions[0].StateHash
+	}
+	status.LastIndex = held
+	status.StateHash = stateHash
+	status.MerkleRoot = proof.FromRoot
+	status.Ready = ready
+	status.PublicKey, status.Signature = nil, nil
+	if key := n.cluster.ledger.options.signingKey; key != nil {
+		api.SignStatus(key, status)
+	}
+	return status, nil
+}
+
+// SubscribeTransactions streams the transactions replicated to the node,
+// continuing with new ones as they are replicated. It returns when the
+// stream's context is done, sending fails or the node goes down.
+func (n *Node) SubscribeTransactions(req *api.SubscribeRequest, stream api.Ledger_SubscribeTransactionsServer) error {
+	ctx := stream.Context()
+	index := req.Index
+	if index < 1 {
+		return api.BadRequestError("Index must be 1 or higher")
+	}
+	held, err := n.held()
+	if err != nil {
+		return err
+	}
+	if index > held+1 {
+		return api.NotFoundError("Requested index is too far in the future")
+	}
+
+	for {
+		if _, err := n.checkSeed(req.NetworkSeed); err != nil {
+			return err
+		}
+		txs, last, err := n.read(ctx, req.NetworkSeed, index, scanBatchSize, held, req.Types)
+		if err != nil {
+			return err
+		}
+		for _, tx := range txs {
+			if err := stream.Send(tx); err != nil {
+				return err
+			}
+		}
+		index = last + 1
+		if index > held {
+			n.waitFor(ctx, index)
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if held, err = n.held(); err != nil {
+			return err
+		}
+	}
+}
+
+// GetTransaction looks up a transaction replicated to the node by its hash.
+func (n *Node) GetTransaction(ctx context.Context, req *api.GetTransactionRequest) (*api.GetTransactionResult, error) {
+	held, err := n.held()
+	if err != nil {
+		return nil, err
+	}
+	res, err := n.cluster.ledger.GetTransaction(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if res.Transaction.Index > held {
+		return nil, api.NotFoundError("Transaction not found")
+	}
+	return res, nil
+}
+
+// GetInclusionProof returns the audit path of a transaction in the Merkle tree
+// of the requested size, or of the transactions replicated to the node if no
+// size is requested.
+func (n *Node) GetInclusionProof(ctx context.Context, req *api.GetInclusionProofRequest) (*api.GetInclusionProofResult, error) {
+	held, err := n.held()
+	if err != nil {
+		return nil, err
+	}
+	if req.TreeSize > held {
+		return nil, api.NotFoundError("Tree size is beyond the last index")
+	}
+	r := *req
+	if r.TreeSize == 0 {
+		r.TreeSize = held
+	}
+	if r.TreeSize == 0 {
+		return nil, api.BadRequestError("Index is out of range for the tree size")
+	}
+	return n.cluster.ledger.GetInclusionProof(ctx, &r)
+}
+
+// GetConsistencyProof returns the consistency proof between two tree sizes,
+// or between the requested from size and the transactions replicated to the
+// node if no to size is requested.
+func (n *Node) GetConsistencyProof(ctx context.Context, req *api.GetConsistencyProofRequest) (*api.GetConsistencyProofResult, error) {
+	held, err := n.held()
+	if err != nil {
+		return nil, err
+	}
+	if req.To > held {
+		return nil, api.NotFoundError("Tree size is beyond the last index")
+	}
+	r := *req
+	if r.To == 0 {
+		r.To = held
+	}
+	if r.To == 0 {
+		// The node holds no transactions, and only the empty tree.
+		seed, err := n.checkSeed(req.NetworkSeed)
+		if err != nil {
+			return nil, err
+		}
+		if r.From != 0 {
+			return nil, api.BadRequestError("Tree sizes are out of order")
+		}
+		root := merkle.RootHash(nil)
+		return &api.GetConsistencyProofResult{
+			NetworkSeed: seed,
+			FromRoot:    root,
+			ToRoot:      root,
+		}, nil
+	}
+	return n.cluster.ledger.GetConsistencyProof(ctx, &r)
+}
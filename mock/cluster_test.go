@@ -0,0 +1,187 @@
+package mock_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/jonboulle/clockwork"
+	"golang.org/x/net/context"
+	"net/http"
+	"time"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/api/rest"
+	"github.com/symbiont-io/assembly-sdk/mock"
+
+	"github.com/nbio/st"
+	"github.com/symbiont-io/assembly-sdk/test/utils"
+	"testing"
+)
+
+// getStatus reads the status of a node over its REST API.
+func getStatus(t *testing.T, url string) *rest.ServerStatusResult {
+	resp, err := http.Get(url + "/")
+	st.Assert(t, err, nil)
+	defer resp.Body.Close()
+	var status rest.ServerStatusResult
+	st.Assert(t, json.NewDecoder(resp.Body).Decode(&status), nil)
+	return &status
+}
+
+func TestClusterLag(t *testing.T) {
+	fakeClock := clockwork.NewFakeClock()
+	c, err := mock.NewCluster(2, mock.WithClock(fakeClock))
+	st.Assert(t, err, nil)
+	defer c.Close()
+	leader, follower := c.Nodes()[0], c.Nodes()[1]
+	follower.SetLag(time.Second)
+	ctx := context.Background()
+
+	_, err = leader.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+	res, err := leader.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 10})
+	st.Assert(t, err, nil)
+	st.Expect(t, len(res.Transactions), 3)
+
+	// The follower doesn't have the transactions yet, but wakes up waiting
+	// readers once they're replicated.
+	status := getStatus(t, follower.URL())
+	st.Expect(t, status.LastIndex, int64(0))
+	st.Expect(t, status.Ready, true)
+	fut := make(chan *api.ReadResult, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+		defer cancel()
+		res, err := follower.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 10})
+		st.Assert(t, err, nil)
+		fut <- res
+	}()
+	fakeClock.BlockUntil(1)
+	select {
+	case <-fut:
+		t.Error("Result ready too soon")
+	default:
+	}
+	fakeClock.Advance(time.Second)
+	select {
+	case res := <-fut:
+		st.Expect(t, len(res.Transactions), 3)
+		st.Expect(t, res.LastIndex, int64(3))
+	case <-time.After(1 * time.Second): // allow time for scheduling
+		t.Error("Result not ready in time")
+	}
+
+	status = getStatus(t, follower.URL())
+	st.Expect(t, status.LastIndex, int64(3))
+	st.Expect(t, status.StateHash, getStatus(t, leader.URL()).StateHash)
+	st.Expect(t, status.MerkleRoot, getStatus(t, leader.URL()).MerkleRoot)
+}
+
+func TestClusterPartition(t *testing.T) {
+	c, err := mock.NewCluster(3)
+	st.Assert(t, err, nil)
+	defer c.Close()
+	nodes := c.Nodes()
+	ctx := context.Background()
+	appendTo := func(n *mock.Node) error {
+		_, err := n.AppendTransactions(ctx, &api.AppendRequest{
+			Transactions: utils.RandomUnsequencedTransactions(1, 100),
+		})
+		return err
+	}
+
+	st.Assert(t, appendTo(nodes[0]), nil)
+	nodes[2].Partition()
+	st.Assert(t, appendTo(nodes[1]), nil)
+	_, ok := appendTo(nodes[2]).(api.ServerError)
+	st.Expect(t, ok, true)
+
+	// The partitioned node still serves what it has.
+	status := getStatus(t, nodes[2].URL())
+	st.Expect(t, status.LastIndex, int64(1))
+	st.Expect(t, status.Ready, false)
+	res, err := nodes[2].ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 10})
+	st.Assert(t, err, nil)
+	st.Expect(t, len(res.Transactions), 1)
+
+	nodes[2].Heal()
+	status = getStatus(t, nodes[2].URL())
+	st.Expect(t, status.LastIndex, int64(2))
+	st.Expect(t, status.Ready, true)
+	st.Expect(t, appendTo(nodes[2]), nil)
+}
+
+func TestClusterCrash(t *testing.T) {
+	fakeClock := clockwork.NewFakeClock()
+	c, err := mock.NewCluster(2, mock.WithClock(fakeClock))
+	st.Assert(t, err, nil)
+	defer c.Close()
+	nodes := c.Nodes()
+	nodes[1].SetLag(time.Minute)
+	ctx := context.Background()
+
+	nodes[1].Crash()
+	_, err = http.Get(nodes[1].URL() + "/")
+	st.Refute(t, err, nil)
+	_, err = nodes[1].ServerStatus(ctx, nil)
+	_, ok := err.(api.ServerError)
+	st.Expect(t, ok, true)
+
+	_, err = nodes[0].AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(2, 100),
+	})
+	st.Assert(t, err, nil)
+
+	// The restarted node catches up after its lag.
+	st.Assert(t, nodes[1].Restart(), nil)
+	status := getStatus(t, nodes[1].URL())
+	st.Expect(t, status.LastIndex, int64(0))
+	st.Expect(t, status.Ready, false)
+	fakeClock.Advance(time.Minute)
+	status = getStatus(t, nodes[1].URL())
+	st.Expect(t, status.LastIndex, int64(2))
+	st.Expect(t, status.Ready, true)
+}
+
+func TestClusterReset(t *testing.T) {
+	fakeClock := clockwork.NewFakeClock()
+	c, err := mock.NewCluster(3, mock.WithClock(fakeClock))
+	st.Assert(t, err, nil)
+	defer c.Close()
+	nodes := c.Nodes()
+	nodes[1].SetLag(time.Minute)
+	ctx := context.Background()
+
+	_, err = nodes[0].AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+	fakeClock.Advance(time.Minute)
+	nodes[2].Partition()
+	st.Expect(t, getStatus(t, nodes[1].URL()).LastIndex, int64(3))
+	st.Expect(t, getStatus(t, nodes[2].URL()).LastIndex, int64(3))
+
+	// No node holds transactions of the old log after the reset.
+	seed, err := c.Reset()
+	st.Assert(t, err, nil)
+	for _, n := range nodes {
+		status := getStatus(t, n.URL())
+		st.Expect(t, status.LastIndex, int64(0))
+		st.Expect(t, status.NetworkSeed, hex.EncodeToString(seed))
+	}
+	st.Expect(t, getStatus(t, nodes[1].URL()).Ready, true)
+	st.Expect(t, getStatus(t, nodes[2].URL()).Ready, false)
+
+	// The new log is replicated as usual.
+	_, err = nodes[0].AppendTransactions(ctx, &api.AppendRequest{
+		NetworkSeed:  seed,
+		Transactions: utils.RandomUnsequencedTransactions(2, 100),
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, getStatus(t, nodes[1].URL()).LastIndex, int64(0))
+	fakeClock.Advance(time.Minute)
+	st.Expect(t, getStatus(t, nodes[1].URL()).LastIndex, int64(2))
+	nodes[2].Heal()
+	st.Expect(t, getStatus(t, nodes[2].URL()).LastIndex, int64(2))
+}
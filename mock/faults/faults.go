@@ -0,0 +1,214 @@
+// Package faults wraps a ledger in a layer that injects faults into requests,
+// so that clients can be tested against a ledger that misbehaves. Requests can
+// be delayed, failed or, for asynchronous appends, dropped, and the ledger can
+// report itself as not ready. The faults can be changed while the ledger is
+// serving requests.
+package faults
+
+import (
+	"github.com/jonboulle/clockwork"
+	"golang.org/x/net/context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
+
+// Ledger is an api.LedgerServer forwarding requests to another ledger, after
+// injecting faults as described by its api.FaultConfig.
+type Ledger struct {
+	ledger  api.LedgerServer
+	options options
+
+	mu     sync.Mutex
+	config api.FaultConfig
+	since  time.Time // When the config was set.
+	rand   *rand.Rand
+}
+
+// NewLedger wraps the ledger in a fault-injection layer. No faults are
+// injected until a config is set with SetConfig.
+func NewLedger(ledger api.LedgerServer, opt ...Option) *Ledger {
+	l := &Ledger{
+		ledger: ledger,
+		options: options{
+			clock:  clockwork.NewRealClock(),
+			source: rand.NewSource(time.Now().UnixNano()),
+		},
+	}
+	for _, o := range opt {
+		o(&l.options)
+	}
+	l.rand = rand.New(l.options.source)
+	l.since = l.options.clock.Now()
+	return l
+}
+
+// Config returns the faults currently injected.
+func (l *Ledger) Config() api.FaultConfig {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.config
+}
+
+// SetConfig replaces the faults injected into requests, which takes effect for
+// requests received from then on. Not ready periods start over.
+func (l *Ledger) SetConfig(c api.FaultConfig) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config = c
+	l.since = l.options.clock.Now()
+	return nil
+}
+
+// draw returns true with the provided probability. The caller must hold the
+// ledger's mutex.
+func (l *Ledger) draw(rate float64) bool {
+	return rate > 0 && l.rand.Float64() < rate
+}
+
+// inject delays a request following the configured latency, and returns the
+// error to fail it with, if any. notFound tells whether the request can fail
+// with a NotFoundError.
+func (l *Ledger) inject(ctx context.Context, notFound bool) error {
+	l.mu.Lock()
+	delay := l.config.Latency.Sample(l.rand)
+	var err error
+	if l.draw(l.config.ServerErrorRate) {
+		err = api.ServerError("Injected server error")
+	} else if notFound && l.draw(l.config.NotFoundRate) {
+		err = api.NotFoundError("Injected not found error")
+	}
+	l.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-l.options.clock.After(delay):
+		case <-ctx.Done():
+		}
+	}
+	return err
+}
+
+// notReady returns true if the ledger should report itself as not ready.
+func (l *Ledger) notReady() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.config.NotReady {
+		return true
+	}
+	if l.config.NotReadyEvery <= 0 {
+		return false
+	}
+	elapsed := l.options.clock.Now().Sub(l.since)
+	return elapsed >= l.config.NotReadyEvery && elapsed%l.config.NotReadyEvery < l.config.NotReadyFor
+}
+
+// ReadTransactions forwards the read to the ledger, unless failing it.
+func (l *Ledger) ReadTransactions(ctx context.Context, req *api.ReadRequest) (*api.ReadResult, error) {
+	if err := l.inject(ctx, true); err != nil {
+		return nil, err
+	}
+	return l.ledger.ReadTransactions(ctx, req)
+}
+
+// AppendTransactions forwards the append to the ledger, unless failing it or,
+// if it's asynchronous, dropping it. Dropped appends get an empty result.
+func (l *Ledger) AppendTransactions(ctx context.Context, req *api.AppendRequest) (*api.AppendResult, error) {
+	if err := l.inject(ctx, false); err != nil {
+		return nil, err
+	}
+	if api.IsAsync(ctx) {
+		l.mu.Lock()
+		drop := l.draw(l.config.AsyncDropRate)
+		l.mu.Unlock()
+		if drop {
+			return &api.AppendResult{}, nil
+		}
+	}
+	return l.ledger.AppendTransactions(ctx, req)
+}
+
+// ServerStatus returns the status of the ledger, unless failing the request.
+// The status isn't ready during the configured not ready periods.
+func (l *Ledger) ServerStatus(ctx context.Context, e *api.Empty) (*api.ServerStatusResult, error) {
+	if err := l.inject(ctx, false); err != nil {
+		return nil, err
+	}
+	status, err := l.ledger.ServerStatus(ctx, e)
+	if err != nil || !l.notReady() {
+		return status, err
+	}
+	// Copy the status rather than changing one the ledger may hold on to.
+	s := *status
+	s.Ready = false
+	return &s, nil
+}
+
+// SubscribeTransactions forwards the subscription to the ledger, unless
+// failing it.
+func (l *Ledger) SubscribeTransactions(req *api.SubscribeRequest, stream api.Ledger_SubscribeTransactionsServer) error {
+	if err := l.inject(stream.Context(), true); err != nil {
+		return err
+	}
+	return l.ledger.SubscribeTransactions(req, stream)
+}
+
+// GetTransaction forwards the lookup to the ledger, unless failing it.
+func (l *Ledger) GetTransaction(ctx context.Context, req *api.GetTransactionRequest) (*api.GetTransactionResult, error) {
+	if err := l.inject(ctx, true); err != nil {
+		return nil, err
+	}
+	return l.ledger.GetTransaction(ctx, req)
+}
+
+// GetInclusionProof forwards the request to the ledger, unless failing it.
+func (l *Ledger) GetInclusionProof(ctx context.Context, req *api.GetInclusionProofRequest) (*api.GetInclusionProofResult, error) {
+	if err := l.inject(ctx, true); err != nil {
+		return nil, err
+	}
+	return l.ledger.GetInclusionProof(ctx, req)
+}
+
+// GetConsistencyProof forwards the request to the ledger, unless failing it.
+func (l *Ledger) GetConsistencyProof(ctx context.Context, req *api.GetConsistencyProofRequest) (*api.GetConsistencyProofResult, error) {
+	if err := l.inject(ctx, true); err != nil {
+		return nil, err
+	}
+	return l.ledger.GetConsistencyProof(ctx, req)
+}
+
+// importer is implemented by ledgers supporting imports, such as mock.Ledger.
+type importer interface {
+	ImportTransactions(seed []byte, next func() (*api.SequencedTransaction, error)) (int64, error)
+}
+
+// ImportTransactions forwards the import to the ledger, if it supports
+// imports, or returns a NotImplementedError. No faults are injected into
+// imports.
+func (l *Ledger) ImportTransactions(seed []byte, next func() (*api.SequencedTransaction, error)) (int64, error) {
+	i, ok := l.ledger.(importer)
+	if !ok {
+		return 0, api.NotImplementedError("Ledger doesn't support imports")
+	}
+	return i.ImportTransactions(seed, next)
+}
+
+// resetter is implemented by ledgers supporting resets, such as mock.Ledger.
+type resetter interface {
+	Reset() ([]byte, error)
+}
+
+// Reset forwards the reset to the ledger, if it supports resets, or returns a
+// NotImplementedError. No faults are injected into resets.
+func (l *Ledger) Reset() ([]byte, error) {
+	r, ok := l.ledger.(resetter)
+	if !ok {
+		return nil, api.NotImplementedError("Ledger doesn't support resets")
+	}
+	return r.Reset()
+}
@@ -0,0 +1,182 @@
+package faults_test
+
+import (
+	"github.com/jonboulle/clockwork"
+	"golang.org/x/net/context"
+	"math/rand"
+	"time"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/mock"
+	"github.com/symbiont-io/assembly-sdk/mock/faults"
+
+	"github.com/nbio/st"
+	"github.com/symbiont-io/assembly-sdk/test/utils"
+	"testing"
+)
+
+func TestNoFaults(t *testing.T) {
+	l := faults.NewLedger(mock.NewLedger())
+	ctx := context.Background()
+	res, err := l.AppendTransactions(api.WithAsync(ctx), &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.LastIndex, int64(3))
+	status, err := l.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+	st.Expect(t, status.Ready, true)
+}
+
+func TestErrorRates(t *testing.T) {
+	l := faults.NewLedger(mock.NewLedger())
+	ctx := context.Background()
+
+	st.Assert(t, l.SetConfig(api.FaultConfig{NotFoundRate: 1}), nil)
+	_, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 1})
+	_, ok := err.(api.NotFoundError)
+	st.Expect(t, ok, true)
+	// Appends can't fail with not found errors.
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	st.Expect(t, err, nil)
+
+	st.Assert(t, l.SetConfig(api.FaultConfig{ServerErrorRate: 1}), nil)
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	_, ok = err.(api.ServerError)
+	st.Expect(t, ok, true)
+	_, err = l.ServerStatus(ctx, nil)
+	_, ok = err.(api.ServerError)
+	st.Expect(t, ok, true)
+
+	st.Assert(t, l.SetConfig(api.FaultConfig{}), nil)
+	status, err := l.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+	st.Expect(t, status.LastIndex, int64(1))
+}
+
+func TestAsyncDrops(t *testing.T) {
+	m := mock.NewLedger()
+	l := faults.NewLedger(m)
+	st.Assert(t, l.SetConfig(api.FaultConfig{AsyncDropRate: 1}), nil)
+	ctx := context.Background()
+
+	_, err := l.AppendTransactions(api.WithAsync(ctx), &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	st.Assert(t, err, nil)
+	status, _ := m.ServerStatus(ctx, nil)
+	st.Expect(t, status.LastIndex, int64(0))
+
+	// Synchronous appends aren't dropped.
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	st.Assert(t, err, nil)
+	status, _ = m.ServerStatus(ctx, nil)
+	st.Expect(t, status.LastIndex, int64(1))
+}
+
+func TestLatency(t *testing.T) {
+	fakeClock := clockwork.NewFakeClock()
+	l := faults.NewLedger(mock.NewLedger(), faults.WithClock(fakeClock))
+	st.Assert(t, l.SetConfig(api.FaultConfig{
+		Latency: api.Latency{Distribution: api.ConstantLatency, Mean: time.Minute},
+	}), nil)
+
+	fut := make(chan *api.ServerStatusResult, 1)
+	go func() {
+		status, err := l.ServerStatus(context.Background(), nil)
+		st.Assert(t, err, nil)
+		fut <- status
+	}()
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(59 * time.Second)
+	select {
+	case <-fut:
+		t.Error("Result ready too soon")
+	default:
+	}
+
+	fakeClock.Advance(time.Second)
+	select {
+	case <-fut:
+	case <-time.After(1 * time.Second): // allow time for scheduling
+		t.Error("Result not ready in time")
+	}
+}
+
+func TestLatencyDistributions(t *testing.T) {
+	for i, c := range []struct {
+		spec     string
+		min, max time.Duration
+	}{
+		{"constant:10ms", 10 * time.Millisecond, 10 * time.Millisecond},
+		{"uniform:10ms,20ms", 10 * time.Millisecond, 20 * time.Millisecond},
+		{"normal:10ms,1ms", time.Nanosecond, time.Second},
+		{"exponential:10ms", time.Nanosecond, time.Hour},
+	} {
+		lat, err := api.ParseLatency(c.spec)
+		st.Assert(t, err, nil)
+		fakeClock := clockwork.NewFakeClock()
+		l := faults.NewLedger(mock.NewLedger(), faults.WithClock(fakeClock),
+			faults.WithRandSource(rand.NewSource(1)))
+		st.Assert(t, l.SetConfig(api.FaultConfig{Latency: lat}), nil)
+
+		done := make(chan struct{})
+		go func() {
+			l.ServerStatus(context.Background(), nil)
+			close(done)
+		}()
+		fakeClock.BlockUntil(1)
+		fakeClock.Advance(c.min - time.Nanosecond)
+		select {
+		case <-done:
+			t.Error("Result ready too soon", i)
+		case <-time.After(10 * time.Millisecond):
+		}
+		fakeClock.Advance(c.max - c.min + time.Nanosecond)
+		select {
+		case <-done:
+		case <-time.After(1 * time.Second): // allow time for scheduling
+			t.Error("Result not ready in time", i)
+		}
+	}
+
+	for i, spec := range []string{"", "constant", "constant:10ms,20ms", "uniform:20ms,10ms", "pareto:10ms", "normal:-1ms,1ms"} {
+		_, err := api.ParseLatency(spec)
+		st.Reject(t, err, nil, i)
+	}
+}
+
+func TestNotReadyPeriods(t *testing.T) {
+	fakeClock := clockwork.NewFakeClock()
+	l := faults.NewLedger(mock.NewLedger(), faults.WithClock(fakeClock))
+	st.Assert(t, l.SetConfig(api.FaultConfig{
+		NotReadyEvery: time.Minute,
+		NotReadyFor:   10 * time.Second,
+	}), nil)
+	ready := func() bool {
+		status, err := l.ServerStatus(context.Background(), nil)
+		st.Assert(t, err, nil)
+		return status.Ready
+	}
+
+	st.Expect(t, ready(), true)
+	fakeClock.Advance(time.Minute)
+	st.Expect(t, ready(), false)
+	fakeClock.Advance(10 * time.Second)
+	st.Expect(t, ready(), true)
+	fakeClock.Advance(55 * time.Second)
+	st.Expect(t, ready(), false)
+
+	st.Assert(t, l.SetConfig(api.FaultConfig{NotReady: true}), nil)
+	st.Expect(t, ready(), false)
+
+	st.Refute(t, l.SetConfig(api.FaultConfig{NotReadyEvery: time.Second, NotReadyFor: time.Minute}), nil)
+	st.Refute(t, l.SetConfig(api.FaultConfig{ServerErrorRate: 2}), nil)
+	st.Expect(t, l.Config().NotReady, true)
+}
@@ -0,0 +1,37 @@
+package faults
+
+import (
+	"github.com/jonboulle/clockwork"
+	"math/rand"
+)
+
+// options holds the configurable options of a faulty ledger. It is not meant
+// to be used directly; the ledger initializes it with default values that are
+// then modified by `With` lambdas passed to `faults.NewLedger`.
+type options struct {
+	// clock is used to delay requests and time not ready periods.
+	clock clockwork.Clock
+
+	// source is the source of the random numbers deciding which faults to
+	// inject.
+	source rand.Source
+}
+
+type Option func(*options)
+
+// WithClock sets the clock used to delay requests and time not ready periods,
+// replacing the real clock.
+func WithClock(c clockwork.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithRandSource sets the source of the random numbers deciding which faults
+// to inject, eg. to make them reproducible. The default source is seeded with
+// the current time.
+func WithRandSource(s rand.Source) Option {
+	return func(o *options) {
+		o.source = s
+	}
+}
@@ -0,0 +1,511 @@
+package mock
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
+
+const (
+	// DefaultSegmentSize is the size at which a FileStore stops appending to
+	// the current segment and starts a new one.
+	DefaultSegmentSize = 64 << 20
+
+	// seedFileName is the name of the file holding the network seed.
+	seedFileName = "seed"
+
+	// segmentSuffix is the file name suffix of log segments. Segments are
+	// named after the index of their first transaction, zero padded so that
+	// lexical and numerical order are the same.
+	segmentSuffix = ".log"
+
+	// recordHeaderSize is the size of the header preceding every record: the
+	// length of the payload, the number of records following it in the same
+	// batch and a CRC32 checksum over that count and the payload.
+	recordHeaderSize = 12
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// FileStore is a Store keeping transactions in an append-only log in a
+// directory on disk. The log is split into segments, each holding a run of
+// transactions encoded as length-prefixed and checksummed protobuf records.
+// Every record carries the number of records still to come in its batch, so
+// that a batch cut short by a crash can be told apart from a complete one.
+// Only the last segment is ever written to. Transactions are read from disk
+// on demand; only the position of each record is held in memory.
+type FileStore struct {
+	dir         string
+	seed        []byte
+	segmentSize int64
+
+	segments  []*segment
+	records   []record
+	stateHash []byte
+
+	active     *os.File
+	activeSize int64
+}
+
+// segment is an open segment file.
+type segment struct {
+	first int64
+	file  *os.File
+}
+
+// record is the location of a transaction record within the segments.
+type record struct {
+	segment int
+	offset  int64
+	size    int64
+}
+
+// OpenFileStore opens the store in dir, creating it and a fresh network seed
+// if the directory is empty. All stored transactions are replayed and have
+// their hashes and state hash chain verified. A batch torn by a crash during
+// the last write is truncated away; any other damage is reported as an error.
+// A segmentSize of 0 selects DefaultSegmentSize.
+func OpenFileStore(dir string, segmentSize int64) (*FileStore, error) {
+	return openFileStore(dir, segmentSize, nil)
+}
+
+// openFileStore opens a FileStore as OpenFileStore does, using the provided
+// seed instead of a random one if dir holds no seed yet.
+func openFileStore(dir string, segmentSize int64, seed []byte) (*FileStore, error) {
+	if segmentSize <= 0 {
+		segmentSize = DefaultSegmentSize
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("Failed to create data directory: %v", err)
+	}
+	s := &FileStore{dir: dir, segmentSize: segmentSize}
+
+	seed, err := readOrCreateSeed(dir, seed)
+	if err != nil {
+		return nil, err
+	}
+	s.seed = seed
+
+	names, err := segmentNames(dir)
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range names {
+		if err := s.openSegment(name, i == len(names)-1); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+
+	if len(names) > 0 {
+		path := filepath.Join(dir, names[len(names)-1])
+		s.active, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("Failed to open segment for writing: %v", err)
+		}
+	}
+	return s, nil
+}
+
+// openSegment replays and verifies the segment with the given file name,
+// adding it and its records to the store.
+func (s *FileStore) openSegment(name string, last bool) error {
+	first, err := strconv.ParseInt(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid segment name %s", name)
+	}
+	if first != s.LastIndex()+1 {
+		return fmt.Errorf("Segment %s doesn't follow index %d", name, s.LastIndex())
+	}
+	path := filepath.Join(s.dir, name)
+	txs, sizes, err := readSegment(path, last)
+	if err != nil {
+		return fmt.Errorf("Failed to read segment %s: %v", name, err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Failed to open segment %s: %v", name, err)
+	}
+	s.segments = append(s.segments, &segment{first, file})
+
+	var offset int64
+	for i, tx := range txs {
+		if err := verifyTransaction(tx, s.LastIndex()+1, s.stateHash); err != nil {
+			return fmt.Errorf("Corrupt segment %s: %v", name, err)
+		}
+		s.records = append(s.records, record{len(s.segments) - 1, offset, sizes[i]})
+		s.stateHash = tx.StateHash
+		offset += sizes[i]
+	}
+	if last {
+		s.activeSize = offset
+	}
+	return nil
+}
+
+// readOrCreateSeed reads the network seed stored in dir. If there is none, the
+// initial seed, or a random one if it's nil, is durably stored.
+func readOrCreateSeed(dir string, initial []byte) ([]byte, error) {
+	path := filepath.Join(dir, seedFileName)
+	seed, err := ioutil.ReadFile(path)
+	if err == nil {
+		return seed, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Failed to read network seed: %v", err)
+	}
+
+	seed = initial
+	if seed == nil {
+		seed = make([]byte, 32)
+		rand.Read(seed)
+	}
+	if err := writeSeed(dir, seed); err != nil {
+		return nil, err
+	}
+	return seed, nil
+}
+
+// writeSeed durably stores the network seed in dir, replacing any earlier one.
+func writeSeed(dir string, seed []byte) error {
+	path := filepath.Join(dir, seedFileName)
+	tmp := path + ".tmp"
+	if err := writeFileSync(tmp, seed); err != nil {
+		return fmt.Errorf("Failed to write network seed: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("Failed to write network seed: %v", err)
+	}
+	return syncDir(dir)
+}
+
+// segmentNames returns the file names of all segments in dir, in order.
+func segmentNames(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list data directory: %v", err)
+	}
+	var names []string
+	for _, info := range infos {
+		if !info.IsDir() && strings.HasSuffix(info.Name(), segmentSuffix) {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// readSegment decodes all records in a segment, returning them along with the
+// size of each record. Only complete batches are returned. If tolerateTail is
+// set, damage reaching the end of the file, ie. a torn or corrupt final record
+// or an incomplete final batch, is assumed to be an interrupted write and the
+// file is truncated to the end of the last complete batch. Damage followed by
+// intact records is an error either way.
+func readSegment(path string, tolerateTail bool) ([]*api.SequencedTransaction, []int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var txs []*api.SequencedTransaction
+	var sizes []int64
+	var offset, committedOffset int64
+	var committed int
+	var pending uint32
+	r := bufio.NewReader(f)
+	for {
+		tx, remaining, n, err := readRecord(r)
+		if err == io.EOF {
+			if committed == len(txs) {
+				return txs, sizes, nil
+			}
+			err = fmt.Errorf("Incomplete batch")
+		} else if err == nil && committed != len(txs) && remaining != pending-1 {
+			err = fmt.Errorf("Inconsistent batch")
+		}
+		if err != nil {
+			// A record that couldn't be read in full runs to the end of the
+			// file, and so does one that ends there.
+			torn := n == 0 || offset+n >= info.Size()
+			if !tolerateTail || !torn {
+				return nil, nil, fmt.Errorf("%v at offset %d", err, offset)
+			}
+			if err := os.Truncate(path, committedOffset); err != nil {
+				return nil, nil, fmt.Errorf("Failed to truncate torn batch: %v", err)
+			}
+			return txs[:committed], sizes[:committed], nil
+		}
+		txs = append(txs, tx)
+		sizes = append(sizes, n)
+		offset += n
+		pending = remaining
+		if remaining == 0 {
+			committed = len(txs)
+			committedOffset = offset
+		}
+	}
+}
+
+// readRecord reads and decodes a single record, returning the transaction, the
+// number of records following it in its batch and the size of the record.
+// io.EOF is only returned at a record boundary. On errors the size is still
+// returned if the record could be read in full, and is 0 otherwise.
+func readRecord(r io.Reader) (*api.SequencedTransaction, uint32, int64, error) {
+	var header [recordHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.EOF {
+			return nil, 0, 0, io.EOF
+		}
+		return nil, 0, 0, fmt.Errorf("Torn record header: %v", err)
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	remaining := binary.BigEndian.Uint32(header[4:8])
+	sum := binary.BigEndian.Uint32(header[8:12])
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, 0, 0, fmt.Errorf("Torn record: %v", err)
+	}
+	size := recordHeaderSize + int64(length)
+	if recordChecksum(header[4:8], payload) != sum {
+		return nil, 0, size, fmt.Errorf("Record checksum mismatch")
+	}
+	tx := &api.SequencedTransaction{}
+	if err := proto.Unmarshal(payload, tx); err != nil {
+		return nil, 0, size, fmt.Errorf("Failed to decode record: %v", err)
+	}
+	return tx, remaining, size, nil
+}
+
+// recordChecksum computes the checksum of a record from its encoded batch
+// count and payload.
+func recordChecksum(remaining, payload []byte) uint32 {
+	return crc32.Update(crc32.Checksum(remaining, crcTable), crcTable, payload)
+}
+
+// verifyTransaction checks that tx has the expected index, that its hash
+// matches its content and that its state hash follows from prevStateHash.
+func verifyTransaction(tx *api.SequencedTransaction, index int64, prevStateHash []byte) error {
+	if tx.Index != index {
+		return fmt.Errorf("Unexpected index (got %d, expected %d)", tx.Index, index)
+	}
+	hash := sha256.Sum256(append([]byte(tx.Type), tx.Data...))
+	if !bytes.Equal(tx.Hash, hash[:]) {
+		return fmt.Errorf("Hash mismatch on transaction %d", index)
+	}
+	stateHash := sha256.Sum256(append(append([]byte{}, prevStateHash...), tx.Hash...))
+	if !bytes.Equal(tx.StateHash, stateHash[:]) {
+		return fmt.Errorf("State hash mismatch on transaction %d", index)
+	}
+	return nil
+}
+
+func (s *FileStore) Seed() []byte {
+	return s.seed
+}
+
+// Append durably writes a batch of transactions to the log. The whole batch is
+// written in one go and committed with a single fsync. A crash will leave at
+// most a partial batch at the tail, which is discarded on the next open.
+func (s *FileStore) Append(txs []*api.SequencedTransaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	if txs[0].Index != s.LastIndex()+1 {
+		return fmt.Errorf("Unexpected index (got %d, expected %d)", txs[0].Index, s.LastIndex()+1)
+	}
+	if s.active == nil || s.activeSize >= s.segmentSize {
+		if err := s.roll(txs[0].Index); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	var header [recordHeaderSize]byte
+	records := make([]record, 0, len(txs))
+	for i, tx := range txs {
+		payload, err := proto.Marshal(tx)
+		if err != nil {
+			return fmt.Errorf("Failed to encode transaction %d: %v", tx.Index, err)
+		}
+		binary.BigEndian.PutUint32(header[0:4], uint32(len(payload)))
+		binary.BigEndian.PutUint32(header[4:8], uint32(len(txs)-i-1))
+		binary.BigEndian.PutUint32(header[8:12], recordChecksum(header[4:8], payload))
+		records = append(records, record{
+			segment: len(s.segments) - 1,
+			offset:  s.activeSize + int64(buf.Len()),
+			size:    recordHeaderSize + int64(len(payload)),
+		})
+		buf.Write(header[:])
+		buf.Write(payload)
+	}
+	n, err := s.active.Write(buf.Bytes())
+	if err != nil {
+		// Drop whatever part of the batch made it to the file, so that the
+		// next append starts on a record boundary.
+		s.active.Truncate(s.activeSize)
+		return fmt.Errorf("Failed to write transactions: %v", err)
+	}
+	if err := s.active.Sync(); err != nil {
+		s.active.Truncate(s.activeSize)
+		return fmt.Errorf("Failed to sync transactions: %v", err)
+	}
+	s.activeSize += int64(n)
+	s.records = append(s.records, records...)
+	s.stateHash = txs[len(txs)-1].StateHash
+	return nil
+}
+
+// roll starts a new segment, beginning at index.
+func (s *FileStore) roll(index int64) error {
+	name := fmt.Sprintf("%020d%s", index, segmentSuffix)
+	path := filepath.Join(s.dir, name)
+	active, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("Failed to create segment: %v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		active.Close()
+		return fmt.Errorf("Failed to open segment: %v", err)
+	}
+	if err := syncDir(s.dir); err != nil {
+		active.Close()
+		file.Close()
+		return err
+	}
+	if s.active != nil {
+		s.active.Close()
+	}
+	s.segments = append(s.segments, &segment{index, file})
+	s.active = active
+	s.activeSize = 0
+	return nil
+}
+
+// Read reads count transactions from disk, starting at index. Indexes counts
+// from 1 and length will be truncated to stay within bounds.
+func (s *FileStore) Read(index int64, count int) ([]*api.SequencedTransaction, error) {
+	i := int(index - 1)
+	if i < 0 || i > len(s.records) {
+		return nil, fmt.Errorf("Index %d out of range", index)
+	}
+	if count > len(s.records)-i {
+		count = len(s.records) - i
+	}
+	txs := make([]*api.SequencedTransaction, 0, count)
+	for _, rec := range s.records[i : i+count] {
+		r := io.NewSectionReader(s.segments[rec.segment].file, rec.offset, rec.size)
+		tx, _, _, err := readRecord(r)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read transaction %d: %v", index, err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+func (s *FileStore) LastIndex() int64 {
+	return int64(len(s.records))
+}
+
+func (s *FileStore) StateHash() []byte {
+	return s.stateHash
+}
+
+// Reset deletes all segments and stores the new network seed. Segments are
+// deleted first, so that a crash can't leave old transactions under the new
+// seed; at worst the store is left empty under the old one. The seed itself is
+// replaced atomically, so the store is never without one.
+func (s *FileStore) Reset(seed []byte) error {
+	if err := s.Close(); err != nil {
+		return err
+	}
+	s.records = nil
+	s.stateHash = nil
+	s.activeSize = 0
+	names, err := segmentNames(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil {
+			return fmt.Errorf("Failed to delete segment %s: %v", name, err)
+		}
+	}
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+	if err := writeSeed(s.dir, seed); err != nil {
+		return err
+	}
+	s.seed = seed
+	return nil
+}
+
+// Close closes all files held by the store.
+func (s *FileStore) Close() error {
+	var err error
+	for _, seg := range s.segments {
+		if e := seg.file.Close(); e != nil {
+			err = e
+		}
+	}
+	s.segments = nil
+	if s.active != nil {
+		if e := s.active.Close(); e != nil {
+			err = e
+		}
+		s.active = nil
+	}
+	return err
+}
+
+// writeFileSync writes data to a new file at path and syncs it to disk.
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir syncs a directory, making file creations and renames in it durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("Failed to open data directory: %v", err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("Failed to sync data directory: %v", err)
+	}
+	return nil
+}
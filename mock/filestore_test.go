@@ -0,0 +1,220 @@
+package mock_test
+
+import (
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/mock"
+
+	"github.com/nbio/st"
+	"github.com/symbiont-io/assembly-sdk/test/utils"
+	"testing"
+)
+
+func TestOpenLedgerPersists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock-ledger")
+	st.Assert(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	l, err := mock.OpenLedger(dir)
+	st.Assert(t, err, nil)
+	ctx := context.Background()
+	status, _ := l.ServerStatus(ctx, nil)
+	txs := utils.RandomUnsequencedTransactions(10, 100)
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{NetworkSeed: status.NetworkSeed, Transactions: txs[:4]})
+	st.Assert(t, err, nil)
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{NetworkSeed: status.NetworkSeed, Transactions: txs[4:]})
+	st.Assert(t, err, nil)
+	before, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 100})
+	st.Assert(t, err, nil)
+	st.Assert(t, l.Close(), nil)
+
+	// Reopen and verify that seed and transactions are unchanged.
+	l, err = mock.OpenLedger(dir)
+	st.Assert(t, err, nil)
+	defer l.Close()
+	reopened, _ := l.ServerStatus(ctx, nil)
+	st.Expect(t, reopened.NetworkSeed, status.NetworkSeed)
+	st.Expect(t, reopened.LastIndex, int64(10))
+	after, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 100})
+	st.Assert(t, err, nil)
+	st.Assert(t, len(after.Transactions), 10)
+	for i, tx := range after.Transactions {
+		st.Expect(t, tx.Data, before.Transactions[i].Data)
+		st.Expect(t, tx.Timestamp, before.Transactions[i].Timestamp)
+		st.Expect(t, tx.StateHash, before.Transactions[i].StateHash)
+	}
+
+	// Replayed transactions can be looked up by hash.
+	got, err := l.GetTransaction(ctx, &api.GetTransactionRequest{Hash: before.Transactions[6].Hash})
+	st.Assert(t, err, nil)
+	st.Expect(t, got.Transaction.Index, int64(7))
+
+	// The state hash chain continues where it left off.
+	res, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.LastIndex, int64(11))
+}
+
+func TestOpenLedgerSeed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock-ledger")
+	st.Assert(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	// The seed is only used for new data directories.
+	l, err := mock.OpenLedger(dir, mock.WithSeed([]byte("seed")))
+	st.Assert(t, err, nil)
+	status, _ := l.ServerStatus(context.Background(), nil)
+	st.Expect(t, status.NetworkSeed, []byte("seed"))
+	st.Assert(t, l.Close(), nil)
+
+	l, err = mock.OpenLedger(dir, mock.WithSeed([]byte("other seed")))
+	st.Assert(t, err, nil)
+	defer l.Close()
+	status, _ = l.ServerStatus(context.Background(), nil)
+	st.Expect(t, status.NetworkSeed, []byte("seed"))
+}
+
+func TestOpenLedgerTornTail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock-ledger")
+	st.Assert(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	l, err := mock.OpenLedger(dir)
+	st.Assert(t, err, nil)
+	ctx := context.Background()
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+	st.Assert(t, l.Close(), nil)
+
+	// Simulate a crash in the middle of writing a record.
+	segments, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	st.Assert(t, len(segments), 1)
+	f, err := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0644)
+	st.Assert(t, err, nil)
+	f.Write([]byte{0, 0, 1, 0, 1, 2, 3})
+	f.Close()
+
+	l, err = mock.OpenLedger(dir)
+	st.Assert(t, err, nil)
+	defer l.Close()
+	status, _ := l.ServerStatus(ctx, nil)
+	st.Expect(t, status.LastIndex, int64(3))
+	res, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.LastIndex, int64(4))
+}
+
+func TestOpenLedgerCorruptSegment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock-ledger")
+	st.Assert(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	l, err := mock.OpenLedger(dir)
+	st.Assert(t, err, nil)
+	_, err = l.AppendTransactions(context.Background(), &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+	st.Assert(t, l.Close(), nil)
+
+	// A segment that doesn't start where the previous one ended is rejected.
+	segments, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	st.Assert(t, len(segments), 1)
+	err = os.Rename(segments[0], filepath.Join(dir, "00000000000000000002.log"))
+	st.Assert(t, err, nil)
+
+	_, err = mock.OpenLedger(dir)
+	st.Refute(t, err, nil)
+}
+
+func TestOpenLedgerTornBatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock-ledger")
+	st.Assert(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	l, err := mock.OpenLedger(dir)
+	st.Assert(t, err, nil)
+	ctx := context.Background()
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(4, 100),
+	})
+	st.Assert(t, err, nil)
+	st.Assert(t, l.Close(), nil)
+
+	// Simulate a crash before the last record of the second batch was
+	// written. The records before it are intact but must be dropped too.
+	segments, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	st.Assert(t, len(segments), 1)
+	info, err := os.Stat(segments[0])
+	st.Assert(t, err, nil)
+	st.Assert(t, os.Truncate(segments[0], info.Size()-1), nil)
+
+	l, err = mock.OpenLedger(dir)
+	st.Assert(t, err, nil)
+	defer l.Close()
+	status, _ := l.ServerStatus(ctx, nil)
+	st.Expect(t, status.LastIndex, int64(3))
+	res, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.LastIndex, int64(4))
+}
+
+func TestOpenLedgerInvalidSegmentName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock-ledger")
+	st.Assert(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "stray.log"), nil, 0644)
+	st.Assert(t, err, nil)
+
+	_, err = mock.OpenLedger(dir)
+	st.Refute(t, err, nil)
+}
+
+func TestOpenLedgerCorruptRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock-ledger")
+	st.Assert(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	l, err := mock.OpenLedger(dir)
+	st.Assert(t, err, nil)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+			Transactions: utils.RandomUnsequencedTransactions(3, 100),
+		})
+		st.Assert(t, err, nil)
+	}
+	st.Assert(t, l.Close(), nil)
+
+	// Damage to the first batch is followed by an intact batch, so it isn't
+	// a torn write and must not be truncated away.
+	segments, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	st.Assert(t, len(segments), 1)
+	data, err := ioutil.ReadFile(segments[0])
+	st.Assert(t, err, nil)
+	data[20] ^= 0xff
+	st.Assert(t, ioutil.WriteFile(segments[0], data, 0644), nil)
+
+	_, err = mock.OpenLedger(dir)
+	st.Refute(t, err, nil)
+	info, err := os.Stat(segments[0])
+	st.Assert(t, err, nil)
+	st.Expect(t, info.Size(), int64(len(data)))
+}
@@ -0,0 +1,99 @@
+package mock
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
+
+// ImportTransactions loads a pre-sequenced history, such as one exported from
+// another ledger, into the empty ledger. The transactions keep their indexes,
+// timestamps and state hashes, and the ledger takes on the provided network
+// seed. next is called for each transaction in turn, and returns io.EOF after
+// the last one.
+//
+// The history is verified as it's loaded: indexes must start at 1 and follow
+// each other, hashes must match the transactions, state hashes must chain and
+// timestamps mustn't go backwards. If any check fails, or next returns another
+// error, nothing is imported and a BadRequestError is returned. A NotEmptyError
+// is returned if the ledger isn't empty. The ledger is locked for the duration
+// of the import, and the index of the last transaction imported is returned.
+func (l *Ledger) ImportTransactions(seed []byte, next func() (*api.SequencedTransaction, error)) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.store.LastIndex() != 0 {
+		return 0, api.NotEmptyError(l.store.LastIndex())
+	}
+	if len(seed) == 0 {
+		return 0, api.BadRequestError("Missing network seed")
+	}
+	original := l.store.Seed()
+	if err := l.store.Reset(seed); err != nil {
+		return 0, api.ServerError(err.Error())
+	}
+	last, err := l.importTransactions(next)
+	if err != nil {
+		// Leave the ledger empty, as it was.
+		l.index = index{}
+		if err := l.store.Reset(original); err != nil {
+			return 0, api.ServerError(err.Error())
+		}
+		return 0, err
+	}
+
+	if last > 0 {
+		// Signal arrival of new data to waiting readers.
+		close(l.newData)
+		l.newData = make(chan struct{})
+	}
+	return last, nil
+}
+
+// importTransactions verifies the transactions returned by next and appends
+// them to the store in batches. The caller must hold the ledger's mutex.
+func (l *Ledger) importTransactions(next func() (*api.SequencedTransaction, error)) (int64, error) {
+	var prev *api.SequencedTransaction
+	batch := make([]*api.SequencedTransaction, 0, scanBatchSize)
+	flush := func() error {
+		if err := l.store.Append(batch); err != nil {
+			return api.ServerError(err.Error())
+		}
+		l.index.add(batch)
+		batch = batch[:0]
+		return nil
+	}
+	for index := int64(1); ; index++ {
+		tx, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, api.BadRequestError(fmt.Sprintf("Failed to read transaction %d: %v", index, err))
+		}
+		var prevStateHash []byte
+		if prev != nil {
+			prevStateHash = prev.StateHash
+			if tx.Timestamp < prev.Timestamp {
+				return 0, api.BadRequestError(fmt.Sprintf("Timestamp of transaction %d is before the previous one", index))
+			}
+		}
+		if err := verifyTransaction(tx, index, prevStateHash); err != nil {
+			return 0, api.BadRequestError(err.Error())
+		}
+		batch = append(batch, tx)
+		if len(batch) == scanBatchSize {
+			if err := flush(); err != nil {
+				return 0, err
+			}
+		}
+		prev = tx
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return 0, err
+		}
+	}
+	return l.store.LastIndex(), nil
+}
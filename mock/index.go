@@ -0,0 +1,106 @@
+package mock
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
+
+// index holds lookup structures built from the transactions in the Store. It
+// is kept in memory and rebuilt from the Store when the ledger is opened.
+type index struct {
+	// lastIndex is the index of the last transaction indexed.
+	lastIndex int64
+
+	// lastTimestamp is the timestamp of the last transaction indexed.
+	lastTimestamp int64
+
+	// hashes maps transaction hashes to the indexes of the transactions with
+	// that hash.
+	hashes map[string]hashIndexes
+
+	// tree is the Merkle tree over the transaction hashes.
+	tree merkleTree
+}
+
+// hashIndexes are the indexes of the first and last transaction with a hash.
+type hashIndexes struct {
+	first, last int64
+}
+
+// add adds sequenced transactions, in order, to the index.
+func (i *index) add(txs []*api.SequencedTransaction) {
+	if i.hashes == nil {
+		i.hashes = make(map[string]hashIndexes)
+	}
+	for _, tx := range txs {
+		h, ok := i.hashes[string(tx.Hash)]
+		if !ok {
+			h.first = tx.Index
+		}
+		h.last = tx.Index
+		i.hashes[string(tx.Hash)] = h
+		i.tree.add(tx.Hash)
+		i.lastIndex = tx.Index
+		i.lastTimestamp = tx.Timestamp
+	}
+}
+
+// updateIndex adds any transactions in the Store that haven't been indexed
+// yet, such as those replayed by a persistent Store, to the index. The caller
+// must hold the ledger's mutex.
+func (l *Ledger) updateIndex() error {
+	for l.index.lastIndex < l.store.LastIndex() {
+		txs, err := l.store.Read(l.index.lastIndex+1, scanBatchSize)
+		if err != nil {
+			return err
+		}
+		l.index.add(txs)
+	}
+	return nil
+}
+
+// GetTransaction looks up a transaction by its hash, returning the first one
+// sequenced if the same transaction has been appended more than once.
+func (l *Ledger) GetTransaction(ctx context.Context, req *api.GetTransactionRequest) (*api.GetTransactionResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.verifySeed(req.NetworkSeed) {
+		return nil, api.NetworkSeedMismatchError(l.store.Seed())
+	}
+	if err := l.updateIndex(); err != nil {
+		return nil, api.ServerError(err.Error())
+	}
+	h, ok := l.index.hashes[string(req.Hash)]
+	if !ok {
+		return nil, api.NotFoundError("Transaction not found")
+	}
+	txs, err := l.store.Read(h.first, 1)
+	if err != nil {
+		return nil, api.ServerError(err.Error())
+	}
+	return &api.GetTransactionResult{
+		NetworkSeed: l.store.Seed(),
+		Transaction: txs[0],
+	}, nil
+}
+
+// duplicateOf returns the index of the most recent transaction with the
+// provided hash, if it's recent enough to be considered a duplicate under the
+// ledger's dedup window. The caller must hold the ledger's mutex and have
+// updated the index.
+func (l *Ledger) duplicateOf(hash []byte) (int64, bool) {
+	window := l.options.dedupWindow
+	if window == DedupOff {
+		return 0, false
+	}
+	h, ok := l.index.hashes[string(hash)]
+	if !ok {
+		return 0, false
+	}
+	if window != DedupFullHistory && h.last <= l.store.LastIndex()-window {
+		return 0, false
+	}
+	return h.last, true
+}
@@ -0,0 +1,166 @@
+package mock
+
+import (
+	"crypto/sha256"
+
+	"golang.org/x/net/context"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/api/merkle"
+)
+
+// merkleTree is the Merkle tree over the hashes of the transactions in the
+// ledger. It keeps the hashes of all complete subtrees, so that the root and
+// proofs for any tree size can be calculated without rehashing every leaf.
+type merkleTree struct {
+	// levels[k] holds the hashes of the complete subtrees of size 2^k, from
+	// left to right. levels[0] holds the leaf hashes.
+	levels [][][]byte
+}
+
+// add appends a transaction hash as a new leaf of the tree.
+func (t *merkleTree) add(hash []byte) {
+	h := merkle.LeafHash(hash)
+	for k := 0; ; k++ {
+		if k == len(t.levels) {
+			t.levels = append(t.levels, nil)
+		}
+		t.levels[k] = append(t.levels[k], h)
+		n := len(t.levels[k])
+		if n%2 == 1 {
+			return
+		}
+		// The new node completes a subtree on the level above.
+		h = merkle.NodeHash(t.levels[k][n-2], h)
+	}
+}
+
+// hash returns the hash of the subtree over the leaves from lo up to, but not
+// including, hi. lo must be a multiple of the largest power of two not larger
+// than hi-lo, which holds for all subtrees of RFC 6962 trees.
+func (t *merkleTree) hash(lo, hi int64) []byte {
+	n := hi - lo
+	if n == 0 {
+		h := sha256.Sum256(nil)
+		return h[:]
+	}
+	if n&(n-1) == 0 {
+		k := 0
+		for int64(1)<<uint(k) < n {
+			k++
+		}
+		return t.levels[k][lo/n]
+	}
+	k := merkle.SplitPoint(n)
+	return merkle.NodeHash(t.hash(lo, lo+k), t.hash(lo+k, hi))
+}
+
+// root returns the root hash of the tree of the provided size.
+func (t *merkleTree) root(size int64) []byte {
+	return t.hash(0, size)
+}
+
+// inclusionPath returns the audit path of leaf m in the subtree over the
+// leaves from lo up to hi.
+func (t *merkleTree) inclusionPath(m, lo, hi int64) [][]byte {
+	if hi-lo == 1 {
+		return nil
+	}
+	k := merkle.SplitPoint(hi - lo)
+	if m < lo+k {
+		return append(t.inclusionPath(m, lo, lo+k), t.hash(lo+k, hi))
+	}
+	return append(t.inclusionPath(m, lo+k, hi), t.hash(lo, lo+k))
+}
+
+// consistencyPath returns the nodes proving that the tree of size m is a
+// prefix of the subtree over the leaves from lo up to hi, following SUBPROOF in
+// RFC 6962. complete tells whether the subtree of size m is the whole older
+// tree, whose root the verifier already has.
+func (t *merkleTree) consistencyPath(m, lo, hi int64, complete bool) [][]byte {
+	n := hi - lo
+	if m == n {
+		if complete {
+			return nil
+		}
+		return [][]byte{t.hash(lo, hi)}
+	}
+	k := merkle.SplitPoint(n)
+	if m <= k {
+		return append(t.consistencyPath(m, lo, lo+k, complete), t.hash(lo+k, hi))
+	}
+	return append(t.consistencyPath(m-k, lo+k, hi, false), t.hash(lo, lo+k))
+}
+
+// GetInclusionProof returns the audit path of a transaction in the Merkle tree
+// of the requested size, or of the whole ledger if no size is requested.
+func (l *Ledger) GetInclusionProof(ctx context.Context, req *api.GetInclusionProofRequest) (*api.GetInclusionProofResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.verifySeed(req.NetworkSeed) {
+		return nil, api.NetworkSeedMismatchError(l.store.Seed())
+	}
+	if err := l.updateIndex(); err != nil {
+		return nil, api.ServerError(err.Error())
+	}
+	size := req.TreeSize
+	if size == 0 {
+		size = l.store.LastIndex()
+	}
+	if size > l.store.LastIndex() {
+		return nil, api.NotFoundError("Tree size is beyond the last index")
+	}
+	if req.Index < 1 || req.Index > size {
+		return nil, api.BadRequestError("Index is out of range for the tree size")
+	}
+	txs, err := l.store.Read(req.Index, 1)
+	if err != nil {
+		return nil, api.ServerError(err.Error())
+	}
+	return &api.GetInclusionProofResult{
+		NetworkSeed: l.store.Seed(),
+		Index:       req.Index,
+		TreeSize:    size,
+		Hash:        txs[0].Hash,
+		Root:        l.index.tree.root(size),
+		AuditPath:   l.index.tree.inclusionPath(req.Index-1, 0, size),
+	}, nil
+}
+
+// GetConsistencyProof returns the proof that the Merkle tree of the requested
+// from size is a prefix of the tree of the requested to size, or of the whole
+// ledger if no to size is requested.
+func (l *Ledger) GetConsistencyProof(ctx context.Context, req *api.GetConsistencyProofRequest) (*api.GetConsistencyProofResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.verifySeed(req.NetworkSeed) {
+		return nil, api.NetworkSeedMismatchError(l.store.Seed())
+	}
+	if err := l.updateIndex(); err != nil {
+		return nil, api.ServerError(err.Error())
+	}
+	to := req.To
+	if to == 0 {
+		to = l.store.LastIndex()
+	}
+	if to > l.store.LastIndex() {
+		return nil, api.NotFoundError("Tree size is beyond the last index")
+	}
+	if req.From < 0 || req.From > to {
+		return nil, api.BadRequestError("Tree sizes are out of order")
+	}
+	var proof [][]byte
+	if req.From > 0 && req.From < to {
+		proof = l.index.tree.consistencyPath(req.From, 0, to, true)
+	}
+	return &api.GetConsistencyProofResult{
+		NetworkSeed: l.store.Seed(),
+		From:        req.From,
+		To:          to,
+		FromRoot:    l.index.tree.root(req.From),
+		ToRoot:      l.index.tree.root(to),
+		Proof:       proof,
+	}, nil
+}
@@ -1,68 +1,129 @@
 // Package mock is a mock ledger implementation. It implements the Ledger
 // interface and has the append only semantics of a real ledger, but that's it.
 // There's no networking and thus no BFT. Storage is in memory and is wiped on
-// restart.
+// restart, unless the ledger is backed by a persistent Store such as the one
+// used by OpenLedger.
 package mock
 
 import (
 	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
+	"fmt"
+	"github.com/jonboulle/clockwork"
 	"golang.org/x/net/context"
+	"io"
 	"sync"
-	"time"
 
 	"github.com/symbiont-io/assembly-sdk/api"
 )
 
-// Ledger acts like a real (single-node) ledger. It holds all written
-// transactions in memory.
+// Ledger acts like a real (single-node) ledger. It sequences transactions and
+// hands them to a Store, by default one holding them in memory.
 type Ledger struct {
-	data      []*api.SequencedTransaction
-	seed      []byte
-	stateHash []byte
+	store   Store
+	index   index
+	options options
 
 	mu      sync.Mutex
 	newData chan struct{}
+
+	// seedHistory records the resets of the ledger, oldest first.
+	seedHistory []*api.SeedReset
 }
 
-// NewLedger create a new mock.Ledger object that implements api.Ledger.
-func NewLedger() *Ledger {
+// newSeed returns a random network seed.
+func newSeed() []byte {
 	seed := make([]byte, 32)
 	rand.Read(seed)
+	return seed
+}
+
+// NewLedger create a new mock.Ledger object that implements api.Ledger, with
+// zero or more options changed from their defaults.
+func NewLedger(opt ...Option) *Ledger {
+	var o options
+	for _, f := range opt {
+		f(&o)
+	}
+	if o.store == nil {
+		seed := o.seed
+		if seed == nil {
+			seed = newSeed()
+		}
+		o.store = NewMemoryStore(seed)
+	}
+	if o.clock == nil {
+		o.clock = clockwork.NewRealClock()
+	}
 
 	l := Ledger{
-		seed:    seed,
+		store:   o.store,
+		options: o,
 		newData: make(chan struct{}),
 	}
 	return &l
 }
 
+// OpenLedger opens a mock.Ledger that persists its transactions in a
+// FileStore in dir, so that they survive a restart. If dir holds data from an
+// earlier run, the transactions are replayed and their state hash chain
+// verified, and the network seed is kept. Otherwise a new ledger is created,
+// with a random seed unless one is set with WithSeed. Other options can be
+// provided as for NewLedger.
+func OpenLedger(dir string, opt ...Option) (*Ledger, error) {
+	var o options
+	for _, f := range opt {
+		f(&o)
+	}
+	store, err := openFileStore(dir, DefaultSegmentSize, o.seed)
+	if err != nil {
+		return nil, err
+	}
+	return NewLedger(append(opt, WithStore(store))...), nil
+}
+
+// Close closes the underlying Store, if it holds any resources.
+func (l *Ledger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if c, ok := l.store.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 // verifySeed verifies that the provided seed matches that of the mock
 // ledger. If no seed is provided, it assumes the client doesn't care about
 // this protection and passes the verification.
 func (l *Ledger) verifySeed(seed []byte) bool {
-	if len(seed) > 0 && !bytes.Equal(seed, l.seed) {
+	if len(seed) > 0 && !bytes.Equal(seed, l.store.Seed()) {
 		return false
 	}
 	return true
 }
 
-// ReadTransactions reads transactions from the in-memory storage of the mock
-// ledger. If no new transactions are available it will wait for new ones until
-// the provided timeout.
+// ReadTransactions reads transactions from the storage of the mock ledger. If
+// no new transactions are available it will wait for new ones until the
+// provided timeout. If types are requested, non-matching transactions are
+// skipped and the result's LastIndex reports how far the store was scanned,
+// which is at most maxScanSize transactions per call, so that a filter that
+// rarely matches doesn't hold up appends. If the ledger is reset while
+// waiting, a NetworkSeedMismatchError is returned.
 func (l *Ledger) ReadTransactions(ctx context.Context, req *api.ReadRequest) (*api.ReadResult, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	if !l.verifySeed(req.NetworkSeed) {
-		return nil, api.NetworkSeedMismatchError(l.seed)
+		return nil, api.NetworkSeedMismatchError(l.store.Seed())
 	}
-	if req.Index > int64(len(l.data))+1 {
+	if req.Index > l.store.LastIndex()+1 {
 		return nil, api.NotFoundError("Requested index is too far in the future")
-	} else if req.Index == int64(len(l.data))+1 {
+	} else if req.Index == l.store.LastIndex()+1 {
 		// Wait for new transactions to arrive.
 		waitCh := l.newData // copy channel while holding mutex
+		seed := l.store.Seed()
 
 		l.mu.Unlock()
 		select {
@@ -70,51 +131,225 @@ func (l *Ledger) ReadTransactions(ctx context.Context, req *api.ReadRequest) (*a
 		case <-ctx.Done():
 		}
 		l.mu.Lock()
-	}
-	return &api.ReadResult{l.seed, l.readData(req.Index, int(req.Count))}, nil
-}
 
-// readData reads a slice of transactions from the data array, starting at
-// index and with length count. Indexes counts from 1 and length will be
-// truncated to stay within bounds.
-func (l *Ledger) readData(index int64, count int) []*api.SequencedTransaction {
-	i := int(index - 1)
-	if count > len(l.data)-i {
-		count = len(l.data) - i
+		if !bytes.Equal(seed, l.store.Seed()) {
+			// The ledger was reset while waiting.
+			return nil, api.NetworkSeedMismatchError(l.store.Seed())
+		}
+	}
+	// Scan the store for matching transactions, until Count of them are found,
+	// maxScanSize transactions are scanned or the end of the store is reached.
+	// Without types all transactions match.
+	res := api.ReadResult{
+		NetworkSeed: l.store.Seed(),
+		LastIndex:   req.Index - 1,
+	}
+	end := req.Index - 1 + maxScanSize
+	for int64(len(res.Transactions)) < req.Count && res.LastIndex < end {
+		batch := end - res.LastIndex
+		if batch > scanBatchSize {
+			batch = scanBatchSize
+		}
+		txs, err := l.store.Read(res.LastIndex+1, int(batch))
+		if err != nil {
+			return nil, api.ServerError(err.Error())
+		}
+		if len(txs) == 0 {
+			break
+		}
+		for _, tx := range txs {
+			if api.MatchesType(tx.Type, req.Types) {
+				res.Transactions = append(res.Transactions, tx)
+			}
+			res.LastIndex = tx.Index
+			if int64(len(res.Transactions)) == req.Count {
+				break
+			}
+		}
 	}
-	return l.data[i : i+count]
+	return &res, nil
 }
 
-// AppendTransactions appends the provided array of transactions to the
-// in-memory storage of the mock ledger and wakes any waiting readers.
+// AppendTransactions sequences the provided array of transactions, appends
+// them to the storage of the mock ledger and wakes any waiting readers. The
+// call doesn't return until the Store has accepted the transactions. The
+// transactions are sequenced in the order provided, and a receipt is returned
+// for each. If dedup is enabled, duplicate transactions are rejected, or
+// skipped with a receipt of the original transaction. If an expected last
+// index is set, it's checked under the same lock as the append, so the
+// transactions are only appended if no others have been in between.
 func (l *Ledger) AppendTransactions(ctx context.Context, req *api.AppendRequest) (*api.AppendResult, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	if !l.verifySeed(req.NetworkSeed) {
-		return nil, api.NetworkSeedMismatchError(l.seed)
+		return nil, api.NetworkSeedMismatchError(l.store.Seed())
 	}
+	if e := req.ExpectedLastIndex; e != nil && e.Index != l.store.LastIndex() {
+		return nil, api.ConflictError(l.store.LastIndex())
+	}
+	if err := l.updateIndex(); err != nil {
+		return nil, api.ServerError(err.Error())
+	}
+
+	index := l.store.LastIndex() + 1
+	prevStateHash := l.store.StateHash()
+	prevTimestamp := l.index.lastTimestamp
+	txs := make([]*api.SequencedTransaction, 0, len(req.Transactions))
+	receipts := make([]*api.Receipt, 0, len(req.Transactions))
+	batch := make(map[string]*api.SequencedTransaction)
+	for i, tx := range req.Transactions {
+		// Fill in missing hashes and reject mismatching ones, so that the
+		// state hash chain can be verified when replaying stored data.
+		hash := sha256.Sum256(append([]byte(tx.Type), tx.Data...))
+		if len(tx.Hash) > 0 && !bytes.Equal(tx.Hash, hash[:]) {
+			return nil, api.BadRequestError("Transaction hash mismatch")
+		}
 
-	index := int64(len(l.data) + 1)
-	for _, tx := range req.Transactions {
-		stateHash := sha256.Sum256(append(l.stateHash, tx.Hash...))
-		l.data = append(l.data, &api.SequencedTransaction{
+		// Check for duplicates, both on the ledger and earlier in the request.
+		if l.options.dedupWindow != DedupOff {
+			original, dup := batch[string(hash[:])]
+			if index, ok := l.duplicateOf(hash[:]); ok && !dup {
+				stored, err := l.store.Read(index, 1)
+				if err != nil {
+					return nil, api.ServerError(err.Error())
+				}
+				original, dup = stored[0], true
+			}
+			if dup {
+				if !l.options.dedupReturnIndex {
+					return nil, api.BadRequestError(fmt.Sprintf(
+						"Duplicate transaction %d, already sequenced at index %d", i, original.Index))
+				}
+				receipt := newReceipt(original)
+				receipt.Duplicate = true
+				receipts = append(receipts, receipt)
+				continue
+			}
+		}
+
+		stateHash := sha256.Sum256(append(prevStateHash, hash[:]...))
+		seq := &api.SequencedTransaction{
 			Type:      tx.Type,
 			Index:     index,
 			Data:      tx.Data,
-			Hash:      tx.Hash,
+			Hash:      hash[:],
 			StateHash: stateHash[:],
-			Timestamp: time.Now().UnixNano(),
-		})
-		l.stateHash = stateHash[:]
+			Timestamp: l.timestamp(prevTimestamp),
+		}
+		txs = append(txs, seq)
+		receipts = append(receipts, newReceipt(seq))
+		batch[string(hash[:])] = seq
+		prevStateHash = stateHash[:]
+		prevTimestamp = seq.Timestamp
 		index++
 	}
+	if len(txs) > 0 {
+		if err := l.store.Append(txs); err != nil {
+			return nil, api.ServerError(err.Error())
+		}
+		l.index.add(txs)
+
+		// Signal arrival of new data to waiting readers.
+		close(l.newData)
+		l.newData = make(chan struct{})
+	}
+
+	if key := l.options.signingKey; key != nil {
+		for _, r := range receipts {
+			api.SignReceipt(key, l.store.Seed(), r)
+		}
+	}
+	return &api.AppendResult{
+		NetworkSeed: l.store.Seed(),
+		LastIndex:   l.store.LastIndex(),
+		Receipts:    receipts,
+	}, nil
+}
+
+// timestamp returns the timestamp of a new transaction, which is the current
+// time unless the clock has stepped back to before the timestamp of the
+// previous transaction, in which case that one is repeated. This keeps
+// timestamps from going backwards.
+func (l *Ledger) timestamp(prev int64) int64 {
+	now := l.options.clock.Now().UnixNano()
+	if now < prev {
+		return prev
+	}
+	return now
+}
+
+// newReceipt returns the receipt of a sequenced transaction.
+func newReceipt(tx *api.SequencedTransaction) *api.Receipt {
+	return &api.Receipt{
+		Index:     tx.Index,
+		Hash:      tx.Hash,
+		StateHash: tx.StateHash,
+		Timestamp: tx.Timestamp,
+	}
+}
+
+// scanBatchSize is the number of transactions a subscription or filtered
+// read reads from the store at a time.
+const scanBatchSize = 100
+
+// maxScanSize is the number of transactions a read scans at most, holding the
+// ledger's lock.
+const maxScanSize = 10 * scanBatchSize
+
+// SubscribeTransactions streams transactions to the subscriber, starting at the
+// requested index and continuing with new transactions as they are appended.
+// It returns when the stream's context is done or sending fails, or with a
+// NetworkSeedMismatchError if the ledger is reset.
+func (l *Ledger) SubscribeTransactions(req *api.SubscribeRequest, stream api.Ledger_SubscribeTransactionsServer) error {
+	ctx := stream.Context()
+	index := req.Index
+	if index < 1 {
+		return api.BadRequestError("Index must be 1 or higher")
+	}
 
-	// Signal arrival of new data to waiting readers.
-	close(l.newData)
-	l.newData = make(chan struct{})
+	l.mu.Lock()
+	if index > l.store.LastIndex()+1 {
+		l.mu.Unlock()
+		return api.NotFoundError("Requested index is too far in the future")
+	}
+	// Hold on to the seed, so that the stream ends if the ledger is reset.
+	seed := req.NetworkSeed
+	if len(seed) == 0 {
+		seed = l.store.Seed()
+	}
+	l.mu.Unlock()
 
-	return &api.AppendResult{l.seed, int64(len(l.data))}, nil
+	for {
+		l.mu.Lock()
+		if !l.verifySeed(seed) {
+			l.mu.Unlock()
+			return api.NetworkSeedMismatchError(l.store.Seed())
+		}
+		txs, err := l.store.Read(index, scanBatchSize)
+		waitCh := l.newData // copy channel while holding mutex
+		l.mu.Unlock()
+		if err != nil {
+			return api.ServerError(err.Error())
+		}
+
+		for _, tx := range txs {
+			if api.MatchesType(tx.Type, req.Types) {
+				if err := stream.Send(tx); err != nil {
+					return err
+				}
+			}
+			index++
+		}
+		if len(txs) == 0 {
+			// Wait for new transactions to arrive.
+			select {
+			case <-waitCh:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
 }
 
 // ServerStatus returns the status of the local node.
@@ -122,11 +357,21 @@ func (l *Ledger) ServerStatus(ctx context.Context, _ *api.Empty) (*api.ServerSta
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	return &api.ServerStatusResult{
+	if err := l.updateIndex(); err != nil {
+		return nil, api.ServerError(err.Error())
+	}
+	status := &api.ServerStatusResult{
 		NetworkType: "mock",
-		NetworkSeed: l.seed,
-		LastIndex:   int64(len(l.data)),
-		ServerTime:  time.Now().UnixNano(),
+		NetworkSeed: l.store.Seed(),
+		LastIndex:   l.store.LastIndex(),
+		ServerTime:  l.options.clock.Now().UnixNano(),
 		Ready:       true, // Mock ledger is always ready.
-	}, nil
+		MerkleRoot:  l.index.tree.root(l.store.LastIndex()),
+		StateHash:   l.store.StateHash(),
+		SeedHistory: append([]*api.SeedReset(nil), l.seedHistory...),
+	}
+	if key := l.options.signingKey; key != nil {
+		api.SignStatus(key, status)
+	}
+	return status, nil
 }
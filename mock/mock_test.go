@@ -1,11 +1,17 @@
 package mock_test
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
 	"github.com/jonboulle/clockwork"
+	"golang.org/x/crypto/ed25519"
 	"golang.org/x/net/context"
+	"io"
 	"time"
 
 	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/api/merkle"
+	clientmerkle "github.com/symbiont-io/assembly-sdk/client/merkle"
 	"github.com/symbiont-io/assembly-sdk/mock"
 
 	"github.com/nbio/st"
@@ -20,7 +26,7 @@ func TestAppendAndRead(t *testing.T) {
 
 	ctx := context.Background()
 	status, _ := l.ServerStatus(ctx, nil)
-	appendRes, err := l.AppendTransactions(ctx, &api.AppendRequest{status.NetworkSeed, txs})
+	appendRes, err := l.AppendTransactions(ctx, &api.AppendRequest{NetworkSeed: status.NetworkSeed, Transactions: txs})
 	st.Assert(t, err, nil)
 	st.Expect(t, appendRes.LastIndex, int64(n))
 
@@ -28,7 +34,7 @@ func TestAppendAndRead(t *testing.T) {
 	go func() {
 		ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 		defer cancel()
-		res, err := l.ReadTransactions(ctx, &api.ReadRequest{nil, 20, 1000})
+		res, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 20, Count: 1000})
 		st.Assert(t, err, nil)
 		fut <- res
 	}()
@@ -52,13 +58,13 @@ func TestReadTooFarAhead(t *testing.T) {
 
 	ctx := context.Background()
 	status, _ := l.ServerStatus(ctx, nil)
-	appendRes, err := l.AppendTransactions(ctx, &api.AppendRequest{status.NetworkSeed, txs})
+	appendRes, err := l.AppendTransactions(ctx, &api.AppendRequest{NetworkSeed: status.NetworkSeed, Transactions: txs})
 	st.Assert(t, err, nil)
 	st.Assert(t, appendRes.LastIndex, int64(n))
 
 	ctx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 	defer cancel()
-	_, err = l.ReadTransactions(ctx, &api.ReadRequest{nil, 102, 1})
+	_, err = l.ReadTransactions(ctx, &api.ReadRequest{Index: 102, Count: 1})
 	st.Refute(t, err, nil)
 	_, ok := err.(api.NotFoundError)
 	st.Assert(t, ok, true)
@@ -68,7 +74,7 @@ func TestBadNetworkSeed(t *testing.T) {
 	l := mock.NewLedger()
 	txs := utils.RandomUnsequencedTransactions(5, 100)
 
-	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{[]byte("bad seed"), txs})
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{NetworkSeed: []byte("bad seed"), Transactions: txs})
 	_, rejected := err.(api.NetworkSeedMismatchError)
 	st.Assert(t, rejected, true)
 }
@@ -80,7 +86,7 @@ func TestPollTimeout(t *testing.T) {
 	fut := make(chan *api.ReadResult, 1)
 	go func() {
 		ctx := utils.NewTestContextWithTimeout(fakeClock, 5*time.Minute)
-		res, err := l.ReadTransactions(ctx, &api.ReadRequest{nil, 1, 1})
+		res, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 1})
 		st.Assert(t, err, nil)
 		fut <- res
 	}()
@@ -111,7 +117,547 @@ func TestRealClock(t *testing.T) {
 	l := mock.NewLedger()
 	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
 	defer cancel()
-	res, err := l.ReadTransactions(ctx, &api.ReadRequest{nil, 1, 1})
+	res, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 1})
 	st.Assert(t, err, nil)
 	st.Expect(t, len(res.Transactions), 0)
 }
+
+func TestReproducibleLedger(t *testing.T) {
+	seed := []byte("fixed seed")
+	txs := utils.RandomUnsequencedTransactions(5, 100)
+	ctx := context.Background()
+	run := func() ([]*api.SequencedTransaction, *api.ServerStatusResult) {
+		fakeClock := clockwork.NewFakeClockAt(time.Unix(1461614515, 0))
+		l := mock.NewLedger(mock.WithSeed(seed), mock.WithClock(fakeClock))
+		for _, tx := range txs {
+			_, err := l.AppendTransactions(ctx, &api.AppendRequest{
+				Transactions: []*api.UnsequencedTransaction{tx},
+			})
+			st.Assert(t, err, nil)
+			fakeClock.Advance(time.Second)
+		}
+		res, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 100})
+		st.Assert(t, err, nil)
+		_, err = l.Reset()
+		st.Assert(t, err, nil)
+		status, err := l.ServerStatus(ctx, nil)
+		st.Assert(t, err, nil)
+		return res.Transactions, status
+	}
+
+	first, status := run()
+	st.Expect(t, status.SeedHistory[0].NetworkSeed, seed)
+	st.Expect(t, first[0].Timestamp, time.Unix(1461614515, 0).UnixNano())
+	st.Expect(t, first[4].Timestamp, time.Unix(1461614519, 0).UnixNano())
+	second, status2 := run()
+	st.Expect(t, second, first)
+	st.Expect(t, status2, status)
+}
+
+func TestMonotonicTimestamps(t *testing.T) {
+	fakeClock := clockwork.NewFakeClock()
+	l := mock.NewLedger(mock.WithClock(fakeClock))
+	ctx := context.Background()
+	appendOne := func() int64 {
+		res, err := l.AppendTransactions(ctx, &api.AppendRequest{
+			Transactions: utils.RandomUnsequencedTransactions(1, 100),
+		})
+		st.Assert(t, err, nil)
+		return res.Receipts[0].Timestamp
+	}
+
+	first := appendOne()
+	fakeClock.Advance(-time.Minute) // the wall clock steps back
+	st.Expect(t, appendOne(), first)
+	fakeClock.Advance(2 * time.Minute)
+	st.Expect(t, appendOne(), first+int64(time.Minute))
+}
+
+func TestAppendHashMismatch(t *testing.T) {
+	l := mock.NewLedger()
+	txs := utils.RandomUnsequencedTransactions(1, 100)
+	txs[0].Hash = []byte("bad hash")
+
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{Transactions: txs})
+	_, rejected := err.(api.BadRequestError)
+	st.Assert(t, rejected, true)
+}
+
+// subscribeStream collects transactions sent on a subscription.
+type subscribeStream struct {
+	api.Ledger_SubscribeTransactionsServer
+	ctx context.Context
+	txs chan *api.SequencedTransaction
+}
+
+func (s *subscribeStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *subscribeStream) Send(tx *api.SequencedTransaction) error {
+	s.txs <- tx
+	return nil
+}
+
+func TestSubscribe(t *testing.T) {
+	l := mock.NewLedger()
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+
+	stream := &subscribeStream{ctx: ctx, txs: make(chan *api.SequencedTransaction)}
+	done := make(chan error, 1)
+	go func() {
+		done <- l.SubscribeTransactions(&api.SubscribeRequest{Index: 2}, stream)
+	}()
+	for i := int64(2); i <= 3; i++ {
+		st.Expect(t, (<-stream.txs).Index, i)
+	}
+
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, (<-stream.txs).Index, int64(4))
+
+	cancel()
+	select {
+	case err := <-done:
+		st.Expect(t, err, context.Canceled)
+	case <-time.After(1 * time.Second):
+		t.Error("Subscription not ended by cancelled context")
+	}
+}
+
+func TestReset(t *testing.T) {
+	l := mock.NewLedger()
+	ctx := context.Background()
+	appended, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+	old, _ := l.ServerStatus(ctx, nil)
+
+	// Waiting readers and subscribers are woken by the reset.
+	read := make(chan error, 1)
+	go func() {
+		_, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 4, Count: 1})
+		read <- err
+	}()
+	stream := &subscribeStream{ctx: ctx, txs: make(chan *api.SequencedTransaction)}
+	subscribed := make(chan error, 1)
+	go func() {
+		subscribed <- l.SubscribeTransactions(&api.SubscribeRequest{Index: 4}, stream)
+	}()
+	time.Sleep(10 * time.Millisecond) // allow readers to start waiting
+
+	seed, err := l.Reset()
+	st.Assert(t, err, nil)
+	st.Reject(t, seed, old.NetworkSeed)
+	for _, ch := range []chan error{read, subscribed} {
+		select {
+		case err := <-ch:
+			st.Expect(t, err, api.NetworkSeedMismatchError(seed))
+		case <-time.After(1 * time.Second):
+			t.Error("Reader not woken by reset")
+		}
+	}
+
+	status, err := l.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+	st.Expect(t, status.NetworkSeed, seed)
+	st.Expect(t, status.LastIndex, int64(0))
+	st.Assert(t, len(status.SeedHistory), 1)
+	st.Expect(t, status.SeedHistory[0].NetworkSeed, old.NetworkSeed)
+	st.Expect(t, status.SeedHistory[0].LastIndex, int64(3))
+	_, err = l.GetTransaction(ctx, &api.GetTransactionRequest{Hash: appended.Receipts[0].Hash})
+	_, ok := err.(api.NotFoundError)
+	st.Expect(t, ok, true)
+
+	// The ledger starts over with the new seed.
+	res, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		NetworkSeed:  seed,
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.LastIndex, int64(1))
+}
+
+func TestSubscribeTooFarAhead(t *testing.T) {
+	l := mock.NewLedger()
+	stream := &subscribeStream{ctx: context.Background()}
+	err := l.SubscribeTransactions(&api.SubscribeRequest{Index: 2}, stream)
+	_, ok := err.(api.NotFoundError)
+	st.Assert(t, ok, true)
+}
+
+func TestReadTypes(t *testing.T) {
+	l := mock.NewLedger()
+	ctx := context.Background()
+	var txs []*api.UnsequencedTransaction
+	for i := 0; i < 250; i++ {
+		typ := "other"
+		if i%50 == 0 {
+			typ = "rare/type"
+		}
+		txs = append(txs, &api.UnsequencedTransaction{Type: typ, Data: []byte{byte(i)}})
+	}
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+
+	// Matching transactions are found past Count and batch boundaries.
+	res, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 2, Count: 3, Types: []string{"rare/*"}})
+	st.Assert(t, err, nil)
+	st.Assert(t, len(res.Transactions), 3)
+	st.Expect(t, res.Transactions[0].Index, int64(51))
+	st.Expect(t, res.Transactions[2].Index, int64(151))
+	st.Expect(t, res.LastIndex, int64(151))
+
+	// Scanning to the end without matches reports the scanned range.
+	res, err = l.ReadTransactions(ctx, &api.ReadRequest{Index: 202, Count: 3, Types: []string{"rare/type"}})
+	st.Assert(t, err, nil)
+	st.Expect(t, len(res.Transactions), 0)
+	st.Expect(t, res.LastIndex, int64(250))
+}
+
+func TestReadTypesScanLimit(t *testing.T) {
+	l := mock.NewLedger()
+	ctx := context.Background()
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(2500, 10),
+	})
+	st.Assert(t, err, nil)
+
+	// A read scans a limited range, and the client continues from its
+	// LastIndex.
+	var last int64
+	for calls := 1; ; calls++ {
+		res, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: last + 1, Count: 1, Types: []string{"unknown"}})
+		st.Assert(t, err, nil)
+		st.Expect(t, len(res.Transactions), 0)
+		st.Assert(t, res.LastIndex > last, true)
+		last = res.LastIndex
+		if last == 2500 {
+			st.Expect(t, calls > 1, true)
+			break
+		}
+	}
+}
+
+func TestGetTransaction(t *testing.T) {
+	l := mock.NewLedger()
+	ctx := context.Background()
+	txs := utils.RandomUnsequencedTransactions(3, 100)
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	// Append a duplicate of the second transaction.
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs[1:2]})
+	st.Assert(t, err, nil)
+
+	read, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 4})
+	st.Assert(t, err, nil)
+	for _, tx := range read.Transactions[:3] {
+		res, err := l.GetTransaction(ctx, &api.GetTransactionRequest{Hash: tx.Hash})
+		st.Assert(t, err, nil)
+		st.Expect(t, res.Transaction, tx)
+	}
+
+	_, err = l.GetTransaction(ctx, &api.GetTransactionRequest{Hash: []byte("unknown")})
+	_, ok := err.(api.NotFoundError)
+	st.Expect(t, ok, true)
+
+	_, err = l.GetTransaction(ctx, &api.GetTransactionRequest{
+		NetworkSeed: []byte("bad seed"),
+		Hash:        read.Transactions[0].Hash,
+	})
+	_, ok = err.(api.NetworkSeedMismatchError)
+	st.Expect(t, ok, true)
+}
+
+func TestAppendReceipts(t *testing.T) {
+	l := mock.NewLedger()
+	ctx := context.Background()
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(2, 100),
+	})
+	st.Assert(t, err, nil)
+
+	txs := utils.RandomUnsequencedTransactions(3, 100)
+	res, err := l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	st.Assert(t, len(res.Receipts), 3)
+	read, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 3, Count: 3})
+	st.Assert(t, err, nil)
+	for i, r := range res.Receipts {
+		tx := read.Transactions[i]
+		st.Expect(t, r.Index, tx.Index)
+		st.Expect(t, tx.Data, txs[i].Data)
+		st.Expect(t, r.Hash, tx.Hash)
+		st.Expect(t, r.StateHash, tx.StateHash)
+		st.Expect(t, r.Timestamp, tx.Timestamp)
+	}
+}
+
+func TestDedupOff(t *testing.T) {
+	l := mock.NewLedger()
+	ctx := context.Background()
+	txs := utils.RandomUnsequencedTransactions(1, 100)
+	for i := 0; i < 2; i++ {
+		_, err := l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+		st.Assert(t, err, nil)
+	}
+	status, _ := l.ServerStatus(ctx, nil)
+	st.Expect(t, status.LastIndex, int64(2))
+}
+
+func TestDedupFullHistory(t *testing.T) {
+	l := mock.NewLedger(mock.WithDedup(mock.DedupFullHistory))
+	ctx := context.Background()
+	txs := utils.RandomUnsequencedTransactions(3, 100)
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs[:1]})
+	st.Assert(t, err, nil)
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(10, 100),
+	})
+	st.Assert(t, err, nil)
+
+	// A duplicate rejects the whole request.
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	_, rejected := err.(api.BadRequestError)
+	st.Assert(t, rejected, true)
+	// So does a duplicate within the request.
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{Transactions: []*api.UnsequencedTransaction{txs[1], txs[1]}})
+	_, rejected = err.(api.BadRequestError)
+	st.Assert(t, rejected, true)
+
+	status, _ := l.ServerStatus(ctx, nil)
+	st.Expect(t, status.LastIndex, int64(11))
+}
+
+func TestDedupWindow(t *testing.T) {
+	l := mock.NewLedger(mock.WithDedup(5))
+	ctx := context.Background()
+	txs := utils.RandomUnsequencedTransactions(1, 100)
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(4, 100),
+	})
+	st.Assert(t, err, nil)
+
+	// Index 1 is within the last 5 transactions.
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	_, rejected := err.(api.BadRequestError)
+	st.Assert(t, rejected, true)
+
+	// Once it has slid out of the window, the transaction is accepted again.
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(1, 100),
+	})
+	st.Assert(t, err, nil)
+	res, err := l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.LastIndex, int64(7))
+}
+
+func TestDedupReturnIndex(t *testing.T) {
+	l := mock.NewLedger(mock.WithDedup(mock.DedupFullHistory), mock.WithDedupReturnIndex())
+	ctx := context.Background()
+	txs := utils.RandomUnsequencedTransactions(3, 100)
+	first, err := l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs[:2]})
+	st.Assert(t, err, nil)
+
+	res, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: []*api.UnsequencedTransaction{txs[1], txs[2], txs[2]},
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.LastIndex, int64(3))
+	st.Assert(t, len(res.Receipts), 3)
+	st.Expect(t, res.Receipts[0].Duplicate, true)
+	st.Expect(t, res.Receipts[0].Index, int64(2))
+	st.Expect(t, res.Receipts[0].StateHash, first.Receipts[1].StateHash)
+	st.Expect(t, res.Receipts[1].Duplicate, false)
+	st.Expect(t, res.Receipts[1].Index, int64(3))
+	st.Expect(t, res.Receipts[2].Duplicate, true)
+	st.Expect(t, res.Receipts[2].Index, int64(3))
+}
+
+func TestAppendExpectedLastIndex(t *testing.T) {
+	l := mock.NewLedger()
+	ctx := context.Background()
+	res, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions:      utils.RandomUnsequencedTransactions(2, 100),
+		ExpectedLastIndex: &api.ExpectedIndex{Index: 0},
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.LastIndex, int64(2))
+
+	// A stale expectation is rejected without appending anything.
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions:      utils.RandomUnsequencedTransactions(1, 100),
+		ExpectedLastIndex: &api.ExpectedIndex{Index: 1},
+	})
+	conflict, ok := err.(api.ConflictError)
+	st.Assert(t, ok, true)
+	st.Expect(t, conflict.LastIndex(), int64(2))
+	status, _ := l.ServerStatus(ctx, nil)
+	st.Expect(t, status.LastIndex, int64(2))
+
+	res, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions:      utils.RandomUnsequencedTransactions(1, 100),
+		ExpectedLastIndex: &api.ExpectedIndex{Index: 2},
+	})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.LastIndex, int64(3))
+}
+
+func TestInclusionProof(t *testing.T) {
+	l := mock.NewLedger()
+	ctx := context.Background()
+	status, err := l.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+	st.Expect(t, status.MerkleRoot, merkle.RootHash(nil))
+
+	txs := utils.RandomUnsequencedTransactions(6, 100)
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{Transactions: txs})
+	st.Assert(t, err, nil)
+	var hashes [][]byte
+	for _, tx := range txs {
+		hash := sha256.Sum256(append([]byte(tx.Type), tx.Data...))
+		hashes = append(hashes, hash[:])
+	}
+	status, err = l.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+	st.Expect(t, status.MerkleRoot, merkle.RootHash(hashes))
+
+	res, err := l.GetInclusionProof(ctx, &api.GetInclusionProofRequest{Index: 5})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.TreeSize, int64(6))
+	st.Expect(t, res.Hash, hashes[4])
+	st.Expect(t, res.Root, status.MerkleRoot)
+	st.Expect(t, clientmerkle.VerifyInclusion(res.Hash, 5, 6, res.AuditPath, status.MerkleRoot), nil)
+
+	_, err = l.GetInclusionProof(ctx, &api.GetInclusionProofRequest{Index: 5, TreeSize: 4})
+	_, ok := err.(api.BadRequestError)
+	st.Expect(t, ok, true)
+	_, err = l.GetInclusionProof(ctx, &api.GetInclusionProofRequest{Index: 5, TreeSize: 7})
+	_, ok = err.(api.NotFoundError)
+	st.Expect(t, ok, true)
+}
+
+func TestConsistencyProof(t *testing.T) {
+	l := mock.NewLedger()
+	ctx := context.Background()
+	_, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(3, 100),
+	})
+	st.Assert(t, err, nil)
+	old, err := l.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+	_, err = l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(4, 100),
+	})
+	st.Assert(t, err, nil)
+	status, err := l.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+
+	res, err := l.GetConsistencyProof(ctx, &api.GetConsistencyProofRequest{From: 3})
+	st.Assert(t, err, nil)
+	st.Expect(t, res.To, int64(7))
+	st.Expect(t, res.FromRoot, old.MerkleRoot)
+	st.Expect(t, res.ToRoot, status.MerkleRoot)
+	st.Expect(t, clientmerkle.VerifyConsistency(3, 7, old.MerkleRoot, status.MerkleRoot, res.Proof), nil)
+
+	_, err = l.GetConsistencyProof(ctx, &api.GetConsistencyProofRequest{From: 5, To: 4})
+	_, ok := err.(api.BadRequestError)
+	st.Expect(t, ok, true)
+	_, err = l.GetConsistencyProof(ctx, &api.GetConsistencyProofRequest{From: 3, To: 8})
+	_, ok = err.(api.NotFoundError)
+	st.Expect(t, ok, true)
+}
+
+func TestSigningKey(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	st.Assert(t, err, nil)
+	l := mock.NewLedger(mock.WithSigningKey(key))
+	ctx := context.Background()
+
+	res, err := l.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(2, 100),
+	})
+	st.Assert(t, err, nil)
+	for _, r := range res.Receipts {
+		st.Expect(t, api.VerifyReceipt(pub, res.NetworkSeed, r), nil)
+	}
+
+	status, err := l.ServerStatus(ctx, nil)
+	st.Assert(t, err, nil)
+	st.Expect(t, status.StateHash, res.Receipts[1].StateHash)
+	st.Expect(t, api.VerifyStatus(pub, status), nil)
+}
+
+// iterate returns a function handing out the transactions one at a time, as
+// used by ImportTransactions.
+func iterate(txs []*api.SequencedTransaction) func() (*api.SequencedTransaction, error) {
+	return func() (*api.SequencedTransaction, error) {
+		if len(txs) == 0 {
+			return nil, io.EOF
+		}
+		tx := txs[0]
+		txs = txs[1:]
+		return tx, nil
+	}
+}
+
+func TestImportTransactions(t *testing.T) {
+	ctx := context.Background()
+	src := mock.NewLedger()
+	_, err := src.AppendTransactions(ctx, &api.AppendRequest{
+		Transactions: utils.RandomUnsequencedTransactions(250, 10),
+	})
+	st.Assert(t, err, nil)
+	res, err := src.ReadTransactions(ctx, &api.ReadRequest{Index: 1, Count: 250})
+	st.Assert(t, err, nil)
+
+	l := mock.NewLedger()
+	last, err := l.ImportTransactions(res.NetworkSeed, iterate(res.Transactions))
+	st.Assert(t, err, nil)
+	st.Expect(t, last, int64(250))
+	want, _ := src.ServerStatus(ctx, nil)
+	got, _ := l.ServerStatus(ctx, nil)
+	st.Expect(t, got.NetworkSeed, want.NetworkSeed)
+	st.Expect(t, got.StateHash, want.StateHash)
+	st.Expect(t, got.MerkleRoot, want.MerkleRoot)
+	read, err := l.ReadTransactions(ctx, &api.ReadRequest{Index: 100, Count: 1})
+	st.Assert(t, err, nil)
+	st.Expect(t, read.Transactions[0], res.Transactions[99])
+
+	// Only empty ledgers can be imported into.
+	_, err = l.ImportTransactions(res.NetworkSeed, iterate(res.Transactions))
+	st.Expect(t, err, api.NotEmptyError(250))
+}
+
+func TestImportTransactionsBroken(t *testing.T) {
+	txs := sequence(0, nil, "a", "b", "c")
+	for i, broken := range [][]*api.SequencedTransaction{
+		{txs[0], txs[2]}, // Gap.
+		txs[1:],          // Not starting at 1.
+		append(txs[:2:2], sequence(2, []byte("other"), "c")...), // State hash not chaining.
+		append(txs[:2:2], &api.SequencedTransaction{ // Timestamp going backwards.
+			Index: 3, Timestamp: 1, Data: txs[2].Data, Hash: txs[2].Hash, StateHash: txs[2].StateHash,
+		}),
+	} {
+		l := mock.NewLedger()
+		status, _ := l.ServerStatus(context.Background(), nil)
+		_, err := l.ImportTransactions([]byte("seed"), iterate(broken))
+		_, ok := err.(api.BadRequestError)
+		st.Expect(t, ok, true, i)
+
+		// Nothing is left of the import.
+		after, _ := l.ServerStatus(context.Background(), nil)
+		st.Expect(t, after.NetworkSeed, status.NetworkSeed, i)
+		st.Expect(t, after.LastIndex, int64(0), i)
+	}
+}
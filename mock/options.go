@@ -0,0 +1,102 @@
+package mock
+
+import (
+	"github.com/jonboulle/clockwork"
+	"golang.org/x/crypto/ed25519"
+)
+
+// options holds the configurable options of a ledger. It is not meant to be
+// used directly; the ledger initializes it with default values that are then
+// modified by `With` lambdas passed to `mock.NewLedger`.
+type options struct {
+	// store is where the ledger keeps its transactions.
+	store Store
+
+	// dedupWindow is the number of most recent transactions an appended
+	// transaction is checked against for duplicates, or one of DedupOff and
+	// DedupFullHistory.
+	dedupWindow int64
+
+	// dedupReturnIndex makes the ledger return the index of the original
+	// transaction for duplicates, rather than rejecting them.
+	dedupReturnIndex bool
+
+	// signingKey is the key the ledger signs statuses and receipts with, if
+	// set.
+	signingKey ed25519.PrivateKey
+
+	// seed is the network seed of a new ledger, random if not set.
+	seed []byte
+
+	// clock is what the ledger reads the time from, for timestamps and its
+	// status.
+	clock clockwork.Clock
+}
+
+const (
+	// DedupOff disables checking for duplicate transactions.
+	DedupOff = 0
+
+	// DedupFullHistory checks appended transactions against every
+	// transaction on the ledger.
+	DedupFullHistory = -1
+)
+
+type Option func(*options)
+
+// WithStore sets the Store the ledger keeps its transactions in, replacing
+// the default MemoryStore with a random network seed.
+func WithStore(s Store) Option {
+	return func(o *options) {
+		o.store = s
+	}
+}
+
+// WithDedup makes the ledger check appended transactions for duplicates,
+// transactions with the same hash as one already sequenced. The window is the
+// number of most recent transactions to check against, or DedupFullHistory.
+// Duplicates are rejected with a BadRequestError, unless WithDedupReturnIndex
+// is set. The default is DedupOff.
+func WithDedup(window int64) Option {
+	return func(o *options) {
+		o.dedupWindow = window
+	}
+}
+
+// WithDedupReturnIndex makes the ledger accept duplicate transactions found by
+// WithDedup without sequencing them again, returning a receipt with the index
+// of the original transaction instead.
+func WithDedupReturnIndex() Option {
+	return func(o *options) {
+		o.dedupReturnIndex = true
+	}
+}
+
+// WithSigningKey makes the ledger sign its statuses and append receipts with
+// the provided Ed25519 private key, so that clients trusting the matching
+// public key can check that they weren't forged.
+func WithSigningKey(key ed25519.PrivateKey) Option {
+	return func(o *options) {
+		o.signingKey = key
+	}
+}
+
+// WithSeed sets the network seed of a new ledger, replacing the default random
+// seed, so that the output of the ledger is reproducible. The seed of a ledger
+// opened with OpenLedger is only set if the data directory is new, and the
+// seed of a Store provided with WithStore is left as it is. When the ledger is
+// reset, the new seed is the SHA256 hash of the old one rather than random.
+func WithSeed(seed []byte) Option {
+	return func(o *options) {
+		o.seed = seed
+	}
+}
+
+// WithClock sets the clock the ledger timestamps transactions and reports its
+// server time with, replacing the real clock, eg. with a fake clock for
+// reproducible timestamps.
+func WithClock(c clockwork.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
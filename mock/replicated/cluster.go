@@ -0,0 +1,173 @@
+package replicated
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/symbiont-io/assembly-sdk/api/rest"
+)
+
+// Cluster runs the nodes of a replicated ledger in a single process, each
+// serving its REST API and Raft traffic on local ports. Nodes can be crashed
+// and restarted to test how clients cope with failovers.
+type Cluster struct {
+	peers   []Peer
+	options []Option
+
+	mu    sync.Mutex
+	nodes []*Node
+	// servers are the REST API servers of the nodes, nil for crashed ones.
+	servers []*http.Server
+}
+
+// NewCluster starts a cluster of n nodes, created with the provided options.
+// The nodes elect a leader shortly after, which gives the ledger its network
+// seed.
+func NewCluster(n int, opt ...Option) (*Cluster, error) {
+	c := &Cluster{
+		options: opt,
+		nodes:   make([]*Node, n),
+		servers: make([]*http.Server, n),
+	}
+	// The listeners are held from the start, so that no other process can
+	// take the ports before the nodes are up.
+	var listeners []net.Listener
+	defer func() {
+		for _, lis := range listeners {
+			lis.Close()
+		}
+	}()
+	for i := 0; i < n; i++ {
+		lis, err := listen("localhost:0")
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, lis)
+		raftLis, err := listen("localhost:0")
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, raftLis)
+		c.peers = append(c.peers, Peer{
+			ID:       fmt.Sprintf("node%d", i),
+			RaftAddr: raftLis.Addr().String(),
+			URL:      "http://" + lis.Addr().String(),
+		})
+	}
+	for i := 0; i < n; i++ {
+		lis, raftLis := listeners[2*i], listeners[2*i+1]
+		if err := c.start(i, lis, raftLis); err != nil {
+			listeners = listeners[2*i+2:]
+			c.Close()
+			return nil, err
+		}
+	}
+	listeners = nil
+	return c, nil
+}
+
+// listen listens on a local TCP address.
+func listen(addr string) (net.Listener, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to listen on %q: %v", addr, err)
+	}
+	return lis, nil
+}
+
+// start starts the ith node, taking Raft traffic on raftLis, and serves its
+// REST API on lis. Both listeners are closed if it fails. The caller must hold
+// the cluster's mutex, or have it to itself.
+func (c *Cluster) start(i int, lis, raftLis net.Listener) error {
+	opt := append(c.options[:len(c.options):len(c.options)], WithRaftListener(raftLis))
+	node, err := NewNode(c.peers[i].ID, c.peers, opt...)
+	if err != nil {
+		lis.Close()
+		return err
+	}
+	server := &http.Server{Handler: rest.NewServer(node).Router()}
+	go server.Serve(lis)
+	c.nodes[i], c.servers[i] = node, server
+	return nil
+}
+
+// Peers returns the nodes of the cluster, as known to each other.
+func (c *Cluster) Peers() []Peer {
+	return c.peers
+}
+
+// Nodes returns the nodes of the cluster. Crashed nodes are stopped, and are
+// replaced by new ones when restarted.
+func (c *Cluster) Nodes() []*Node {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*Node(nil), c.nodes...)
+}
+
+// URLs returns the base URLs of the REST APIs of the nodes of the cluster.
+func (c *Cluster) URLs() []string {
+	urls := make([]string, 0, len(c.peers))
+	for _, p := range c.peers {
+		urls = append(urls, p.URL)
+	}
+	return urls
+}
+
+// Leader returns the index of the node that's leader, or -1 if none of the
+// nodes that are up is.
+func (c *Cluster) Leader() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, n := range c.nodes {
+		if c.servers[i] != nil && n.IsLeader() {
+			return i
+		}
+	}
+	return -1
+}
+
+// Crash stops the ith node and its REST API, losing its Raft state and
+// replica, as if its process had been killed.
+func (c *Cluster) Crash(i int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.servers[i] == nil {
+		return nil
+	}
+	c.servers[i].Close()
+	c.servers[i] = nil
+	return c.nodes[i].Close()
+}
+
+// Restart starts a crashed node anew. It rejoins the cluster with an empty
+// replica, and catches up from the leader.
+func (c *Cluster) Restart(i int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.servers[i] != nil {
+		return nil
+	}
+	lis, err := listen(c.peers[i].URL[len("http://"):])
+	if err != nil {
+		return err
+	}
+	raftLis, err := listen(c.peers[i].RaftAddr)
+	if err != nil {
+		lis.Close()
+		return err
+	}
+	return c.start(i, lis, raftLis)
+}
+
+// Close stops all nodes of the cluster.
+func (c *Cluster) Close() error {
+	var err error
+	for i := range c.nodes {
+		if e := c.Crash(i); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,143 @@
+package replicated
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/raft"
+	"github.com/jonboulle/clockwork"
+	"golang.org/x/net/context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/mock"
+)
+
+// Operations of the commands in the Raft log.
+const (
+	// opInit gives the ledger its network seed. Only the first one committed
+	// takes effect.
+	opInit = "init"
+
+	// opAppend appends transactions to the ledger.
+	opAppend = "append"
+)
+
+// command is an entry of the Raft log, applied to the ledger replica of every
+// node in the same order.
+type command struct {
+	Op string `json:"op"`
+
+	// Seed is the network seed set by opInit.
+	Seed []byte `json:"seed,omitempty"`
+
+	// Time is when the leader received the append, which the transactions
+	// are timestamped with, in nanoseconds since the Unix epoch.
+	Time int64 `json:"time,omitempty"`
+
+	// Request is the protobuf encoded api.AppendRequest of opAppend.
+	Request []byte `json:"request,omitempty"`
+}
+
+// encode returns the command encoded for the Raft log.
+func (c *command) encode() ([]byte, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to encode command: %v", err)
+	}
+	return data, nil
+}
+
+// applied is the outcome of applying a command, handed back by Raft to the
+// node that submitted it.
+type applied struct {
+	res *api.AppendResult
+	err error
+}
+
+// fsm is the Raft state machine of a node, applying the commands in the log to
+// its ledger replica. Replicas read the time from the log rather than a clock
+// of their own, so that every replica sequences the same transactions with the
+// same timestamps, and therefore the same state hashes.
+type fsm struct {
+	ledger *mock.Ledger
+	clock  clockwork.FakeClock
+
+	mu          sync.Mutex
+	initialized bool
+	// seeded is closed once the replica is initialized.
+	seeded chan struct{}
+}
+
+// newFSM creates a state machine applying the log to a new ledger replica,
+// created with the provided options.
+func newFSM(opt ...mock.Option) *fsm {
+	clock := clockwork.NewFakeClock()
+	return &fsm{
+		ledger: mock.NewLedger(append(opt, mock.WithClock(clock))...),
+		clock:  clock,
+		seeded: make(chan struct{}),
+	}
+}
+
+// isInitialized tells whether the ledger replica has been given its network
+// seed.
+func (f *fsm) isInitialized() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.initialized
+}
+
+// Apply applies a committed command to the ledger replica. Raft applies
+// commands one at a time, in log order.
+func (f *fsm) Apply(l *raft.Log) interface{} {
+	var c command
+	if err := json.Unmarshal(l.Data, &c); err != nil {
+		return applied{err: api.ServerError(fmt.Sprintf("Failed to decode log entry %d: %v", l.Index, err))}
+	}
+	switch c.Op {
+	case opInit:
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.initialized {
+			return applied{}
+		}
+		_, err := f.ledger.ImportTransactions(c.Seed, func() (*api.SequencedTransaction, error) {
+			return nil, io.EOF
+		})
+		if err != nil {
+			return applied{err: err}
+		}
+		f.initialized = true
+		close(f.seeded)
+		return applied{}
+
+	case opAppend:
+		if !f.isInitialized() {
+			return applied{err: api.ServerError("Ledger has no network seed yet")}
+		}
+		var req api.AppendRequest
+		if err := proto.Unmarshal(c.Request, &req); err != nil {
+			return applied{err: api.ServerError(fmt.Sprintf("Failed to decode append in log entry %d: %v", l.Index, err))}
+		}
+		f.clock.Advance(time.Unix(0, c.Time).Sub(f.clock.Now()))
+		res, err := f.ledger.AppendTransactions(context.Background(), &req)
+		return applied{res, err}
+	}
+	return applied{err: api.ServerError(fmt.Sprintf("Unknown operation %q in log entry %d", c.Op, l.Index))}
+}
+
+// errNoSnapshots is returned when Raft asks for a snapshot. Nodes keep the
+// whole Raft log instead, as their replicas keep the whole ledger anyway, and a
+// node that falls behind or restarts catches up by replaying it.
+var errNoSnapshots = fmt.Errorf("Snapshots are not supported")
+
+func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
+	return nil, errNoSnapshots
+}
+
+func (f *fsm) Restore(io.ReadCloser) error {
+	return errNoSnapshots
+}
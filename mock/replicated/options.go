@@ -0,0 +1,80 @@
+package replicated
+
+import (
+	"github.com/jonboulle/clockwork"
+	"io"
+	"net"
+
+	"github.com/symbiont-io/assembly-sdk/mock"
+)
+
+// options holds the configurable options of a node. It is not meant to be
+// used directly; the node initializes it with default values that are then
+// modified by `With` lambdas passed to `replicated.NewNode`.
+type options struct {
+	// seed is the network seed the node publishes if it's the first leader
+	// of the cluster, random if not set.
+	seed []byte
+
+	// ledgerOptions are the options of the ledger replica the node applies
+	// the Raft log to.
+	ledgerOptions []mock.Option
+
+	// clock is what the node reads the time from, for timestamps of the
+	// transactions it sequences as leader and its status.
+	clock clockwork.Clock
+
+	// logOutput is where Raft logs to.
+	logOutput io.Writer
+
+	// raftListener is the listener the node takes Raft traffic on, if it
+	// isn't to listen on its Raft address itself.
+	raftListener net.Listener
+}
+
+type Option func(*options)
+
+// WithSeed sets the network seed of the ledger, replacing the default random
+// seed. The seed is only used by the node that first becomes leader of a new
+// cluster, which publishes it to the others, so every node should be given
+// the same one.
+func WithSeed(seed []byte) Option {
+	return func(o *options) {
+		o.seed = seed
+	}
+}
+
+// WithLedgerOptions sets the options of the mock.Ledger the node replicates
+// the ledger into, eg. mock.WithDedup. Every node of a cluster must be given
+// the same options, or their replicas will diverge. The clock of the replica
+// is replaced by one following the Raft log, and its statuses are amended by
+// the node, so only receipts are signed with mock.WithSigningKey.
+func WithLedgerOptions(opt ...mock.Option) Option {
+	return func(o *options) {
+		o.ledgerOptions = append(o.ledgerOptions, opt...)
+	}
+}
+
+// WithClock sets the clock the node timestamps transactions with while it's
+// leader and reports its server time with, replacing the real clock.
+func WithClock(c clockwork.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithRaftListener makes the node take Raft traffic on lis, which must be
+// listening on the node's Raft address, rather than listen on it itself. The
+// node closes lis when it's closed, or if it fails to start.
+func WithRaftListener(lis net.Listener) Option {
+	return func(o *options) {
+		o.raftListener = lis
+	}
+}
+
+// WithLogOutput makes Raft log to w, rather than discarding its logs.
+func WithLogOutput(w io.Writer) Option {
+	return func(o *options) {
+		o.logOutput = w
+	}
+}
@@ -0,0 +1,373 @@
+// Package replicated runs a ledger replicated across several nodes with Raft,
+// so that clients can be tested against a ledger that survives nodes going
+// down. Each node keeps a mock.Ledger replica, which it applies the Raft log
+// to. Appends received by a follower are forwarded to the leader's REST API,
+// and the leader sequences them by committing them to the log. Reads are
+// served by every node from its own replica, which may lag slightly behind
+// the leader's.
+//
+// Nodes can run in separate processes or, with NewCluster, in a single one.
+// Raft state is kept in memory, so a restarted node rejoins with an empty
+// replica and catches up from the leader. The cluster stays available as long
+// as a majority of its nodes are up.
+package replicated
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/hashicorp/raft"
+	"github.com/jonboulle/clockwork"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/client/rest"
+)
+
+const (
+	// Raft timeouts, much shorter than the defaults of the Raft library as
+	// the nodes run on a single machine.
+	heartbeatTimeout   = 200 * time.Millisecond
+	electionTimeout    = 200 * time.Millisecond
+	leaderLeaseTimeout = 100 * time.Millisecond
+	commitTimeout      = 5 * time.Millisecond
+
+	// applyTimeout is how long the leader waits for a command to be
+	// committed and applied.
+	applyTimeout = 10 * time.Second
+)
+
+// Peer is a node of a replicated ledger, as known to the other nodes.
+type Peer struct {
+	// ID identifies the node in the Raft cluster.
+	ID string
+
+	// RaftAddr is the address the node's Raft transport listens on, eg.
+	// "localhost:7000".
+	RaftAddr string
+
+	// URL is the base URL of the node's REST API, which followers forward
+	// appends to, eg. "http://localhost:4000".
+	URL string
+}
+
+// ParsePeers parses a comma-separated list of peers, each in the form
+// `<id>@<raft address>@<url>`. Eg. `a@localhost:7000@http://localhost:4000`.
+func ParsePeers(s string) ([]Peer, error) {
+	var peers []Peer
+	ids := make(map[string]bool)
+	for _, p := range strings.Split(s, ",") {
+		parts := strings.SplitN(p, "@", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("Invalid peer %q", p)
+		}
+		if ids[parts[0]] {
+			return nil, fmt.Errorf("Duplicate peer %q", parts[0])
+		}
+		ids[parts[0]] = true
+		peers = append(peers, Peer{ID: parts[0], RaftAddr: parts[1], URL: parts[2]})
+	}
+	return peers, nil
+}
+
+// Node is a node of a replicated ledger. It implements api.LedgerServer, and
+// should be served on the URL it's known by to its peers, eg. by a
+// rest.Server.
+type Node struct {
+	id        string
+	options   options
+	fsm       *fsm
+	raft      *raft.Raft
+	transport *raft.NetworkTransport
+
+	// clients are the REST clients of the other nodes, by ID.
+	clients map[string]*client.Client
+
+	done chan struct{}
+}
+
+// NewNode starts the node with the provided ID, one of the peers, which are
+// all the nodes of the ledger. The node listens for Raft traffic on its
+// address, and joins the other nodes as they come up. Once a majority is up,
+// they elect a leader, which gives the ledger its network seed if it's new.
+func NewNode(id string, peers []Peer, opt ...Option) (*Node, error) {
+	n := &Node{
+		id: id,
+		options: options{
+			clock:     clockwork.NewRealClock(),
+			logOutput: ioutil.Discard,
+		},
+		clients: make(map[string]*client.Client),
+		done:    make(chan struct{}),
+	}
+	for _, o := range opt {
+		o(&n.options)
+	}
+
+	var self *Peer
+	var servers []raft.Server
+	for i, p := range peers {
+		servers = append(servers, raft.Server{
+			ID:      raft.ServerID(p.ID),
+			Address: raft.ServerAddress(p.RaftAddr),
+		})
+		if p.ID == id {
+			self = &peers[i]
+		} else {
+			n.clients[p.ID] = client.New(p.URL, client.WithProtobuf(true))
+		}
+	}
+	if self == nil {
+		if n.options.raftListener != nil {
+			n.options.raftListener.Close()
+		}
+		return nil, fmt.Errorf("Node %q is not one of the peers", id)
+	}
+
+	conf := raft.DefaultConfig()
+	conf.LocalID = raft.ServerID(id)
+	conf.HeartbeatTimeout = heartbeatTimeout
+	conf.ElectionTimeout = electionTimeout
+	conf.LeaderLeaseTimeout = leaderLeaseTimeout
+	conf.CommitTimeout = commitTimeout
+	conf.SnapshotThreshold = math.MaxUint64
+	conf.LogOutput = n.options.logOutput
+	conf.LogLevel = "INFO"
+
+	var transport *raft.NetworkTransport
+	if lis := n.options.raftListener; lis != nil {
+		transport = raft.NewNetworkTransport(&streamLayer{lis}, 3, applyTimeout, n.options.logOutput)
+	} else {
+		var err error
+		transport, err = raft.NewTCPTransport(self.RaftAddr, nil, 3, applyTimeout, n.options.logOutput)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to listen on %q: %v", self.RaftAddr, err)
+		}
+	}
+	n.transport = transport
+	store := raft.NewInmemStore()
+	snapshots := raft.NewInmemSnapshotStore()
+	// Every node bootstraps the cluster with the same configuration, so it
+	// doesn't matter which of them comes up first.
+	err := raft.BootstrapCluster(conf, store, store, snapshots, transport, raft.Configuration{Servers: servers})
+	if err != nil {
+		transport.Close()
+		return nil, fmt.Errorf("Failed to bootstrap Raft: %v", err)
+	}
+	n.fsm = newFSM(n.options.ledgerOptions...)
+	n.raft, err = raft.NewRaft(conf, n.fsm, store, store, snapshots, transport)
+	if err != nil {
+		transport.Close()
+		return nil, fmt.Errorf("Failed to start Raft: %v", err)
+	}
+
+	go n.initialize()
+	return n, nil
+}
+
+// streamLayer carries Raft traffic over TCP, accepting connections on a
+// listener handed to the node rather than one of its own.
+type streamLayer struct {
+	net.Listener
+}
+
+func (s *streamLayer) Dial(address raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("tcp", string(address), timeout)
+}
+
+// ID returns the ID of the node.
+func (n *Node) ID() string {
+	return n.id
+}
+
+// Leader returns the ID of the leader, as far as the node knows, or an empty
+// string if there's none.
+func (n *Node) Leader() string {
+	_, id := n.raft.LeaderWithID()
+	return string(id)
+}
+
+// IsLeader tells whether the node is the leader.
+func (n *Node) IsLeader() bool {
+	return n.raft.State() == raft.Leader
+}
+
+// WaitLeader blocks until the node knows of a leader other than old, which may
+// be empty, and returns its ID. It returns the context's error if it's done
+// first.
+func (n *Node) WaitLeader(ctx context.Context, old string) (string, error) {
+	var leader string
+	err := n.wait(ctx, nil, func() bool {
+		leader = n.Leader()
+		return leader != "" && leader != old
+	})
+	return leader, err
+}
+
+// WaitReady blocks until the node is ready, ie. the ledger has its network
+// seed and the node knows of a leader. It returns the context's error if it's
+// done first.
+func (n *Node) WaitReady(ctx context.Context) error {
+	return n.wait(ctx, n.fsm.seeded, func() bool {
+		return n.fsm.isInitialized() && n.Leader() != ""
+	})
+}
+
+// wait blocks until cond is true, checking it whenever the node observes a
+// change of leader or changed is closed, or until the context is done.
+func (n *Node) wait(ctx context.Context, changed <-chan struct{}, cond func() bool) error {
+	observations := make(chan raft.Observation, 1)
+	observer := raft.NewObserver(observations, false, func(o *raft.Observation) bool {
+		_, ok := o.Data.(raft.LeaderObservation)
+		return ok
+	})
+	n.raft.RegisterObserver(observer)
+	defer n.raft.DeregisterObserver(observer)
+	for !cond() {
+		select {
+		case <-observations:
+		case <-changed:
+			// Closed channels stay ready, so only check them once.
+			changed = nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close stops the node, which leaves the other nodes to carry on without it.
+func (n *Node) Close() error {
+	close(n.done)
+	err := n.raft.Shutdown().Error()
+	n.transport.Close()
+	if err != nil {
+		return fmt.Errorf("Failed to shut down Raft: %v", err)
+	}
+	return n.fsm.ledger.Close()
+}
+
+// initialize gives the ledger its network seed whenever the node becomes
+// leader of a cluster whose ledger hasn't got one yet. If it fails, the
+// node has lost leadership and the next leader takes over.
+func (n *Node) initialize() {
+	for {
+		select {
+		case leader := <-n.raft.LeaderCh():
+			if !leader || n.fsm.isInitialized() {
+				continue
+			}
+			// Apply the commands of earlier leaders first, in case one of
+			// them gave the ledger a seed.
+			if err := n.raft.Barrier(applyTimeout).Error(); err != nil || n.fsm.isInitialized() {
+				continue
+			}
+			seed := n.options.seed
+			if seed == nil {
+				seed = make([]byte, 32)
+				rand.Read(seed)
+			}
+			c := command{Op: opInit, Seed: seed}
+			if data, err := c.encode(); err == nil {
+				n.raft.Apply(data, applyTimeout)
+			}
+		case <-n.done:
+			return
+		}
+	}
+}
+
+// ReadTransactions reads transactions from the node's replica.
+func (n *Node) ReadTransactions(ctx context.Context, req *api.ReadRequest) (*api.ReadResult, error) {
+	return n.fsm.ledger.ReadTransactions(ctx, req)
+}
+
+// AppendTransactions sequences the transactions by committing them to the
+// Raft log, if the node is the leader, and returns once they're applied to its
+// replica. Otherwise the append is forwarded to the leader. A ServerError is
+// returned if there's no leader, or if it loses leadership before the
+// transactions are committed, in which case they may or may not have been
+// appended.
+func (n *Node) AppendTransactions(ctx context.Context, req *api.AppendRequest) (*api.AppendResult, error) {
+	if !n.IsLeader() {
+		return n.forward(ctx, req)
+	}
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return nil, api.BadRequestError(fmt.Sprintf("Failed to encode request: %v", err))
+	}
+	c := command{Op: opAppend, Time: n.options.clock.Now().UnixNano(), Request: data}
+	data, err = c.encode()
+	if err != nil {
+		return nil, api.ServerError(err.Error())
+	}
+	f := n.raft.Apply(data, applyTimeout)
+	if err := f.Error(); err != nil {
+		return nil, api.ServerError(fmt.Sprintf("Failed to commit transactions: %v", err))
+	}
+	res := f.Response().(applied)
+	return res.res, res.err
+}
+
+// forward forwards an append to the leader.
+func (n *Node) forward(ctx context.Context, req *api.AppendRequest) (*api.AppendResult, error) {
+	c, ok := n.clients[n.Leader()]
+	if !ok {
+		return nil, api.ServerError("No leader to forward the append to")
+	}
+	// The client checks the receipts against the hashes of the transactions,
+	// so fill in any that are missing.
+	r := *req
+	r.Transactions = make([]*api.UnsequencedTransaction, len(req.Transactions))
+	for i, tx := range req.Transactions {
+		if len(tx.Hash) == 0 {
+			t := *tx
+			hash := sha256.Sum256(append([]byte(tx.Type), tx.Data...))
+			t.Hash = hash[:]
+			tx = &t
+		}
+		r.Transactions[i] = tx
+	}
+	return c.AppendTransactions(ctx, &r)
+}
+
+// ServerStatus returns the status of the node's replica. The node is ready
+// once the ledger has its network seed and there's a leader.
+func (n *Node) ServerStatus(ctx context.Context, e *api.Empty) (*api.ServerStatusResult, error) {
+	status, err := n.fsm.ledger.ServerStatus(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	status.NetworkType = "replicated"
+	status.ServerTime = n.options.clock.Now().UnixNano()
+	status.Ready = n.fsm.isInitialized() && n.Leader() != ""
+	// The signature no longer matches the amended status.
+	status.PublicKey, status.Signature = nil, nil
+	return status, nil
+}
+
+// SubscribeTransactions streams transactions from the node's replica.
+func (n *Node) SubscribeTransactions(req *api.SubscribeRequest, stream api.Ledger_SubscribeTransactionsServer) error {
+	return n.fsm.ledger.SubscribeTransactions(req, stream)
+}
+
+// GetTransaction looks up a transaction in the node's replica.
+func (n *Node) GetTransaction(ctx context.Context, req *api.GetTransactionRequest) (*api.GetTransactionResult, error) {
+	return n.fsm.ledger.GetTransaction(ctx, req)
+}
+
+// GetInclusionProof returns an inclusion proof from the node's replica.
+func (n *Node) GetInclusionProof(ctx context.Context, req *api.GetInclusionProofRequest) (*api.GetInclusionProofResult, error) {
+	return n.fsm.ledger.GetInclusionProof(ctx, req)
+}
+
+// GetConsistencyProof returns a consistency proof from the node's replica.
+func (n *Node) GetConsistencyProof(ctx context.Context, req *api.GetConsistencyProofRequest) (*api.GetConsistencyProofResult, error) {
+	return n.fsm.ledger.GetConsistencyProof(ctx, req)
+}
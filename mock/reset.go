@@ -0,0 +1,40 @@
+package mock
+
+import (
+	"crypto/sha256"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
+
+// Reset wipes the transactions of the ledger and replaces its network seed
+// with a new random one, as if a new ledger had been created in its place. If
+// the ledger was created WithSeed, the new seed is instead the SHA256 hash of
+// the old one, so that resets are reproducible. The old seed is recorded in
+// the seed history returned by ServerStatus, and readers waiting for new
+// transactions are woken up with a NetworkSeedMismatchError holding the new
+// seed, which is returned.
+func (l *Ledger) Reset() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	reset := &api.SeedReset{
+		NetworkSeed: l.store.Seed(),
+		LastIndex:   l.store.LastIndex(),
+		ResetTime:   l.options.clock.Now().UnixNano(),
+	}
+	seed := newSeed()
+	if l.options.seed != nil {
+		h := sha256.Sum256(l.store.Seed())
+		seed = h[:]
+	}
+	if err := l.store.Reset(seed); err != nil {
+		return nil, api.ServerError(err.Error())
+	}
+	l.index = index{}
+	l.seedHistory = append(l.seedHistory, reset)
+
+	// Wake waiting readers, who will find that the seed has changed.
+	close(l.newData)
+	l.newData = make(chan struct{})
+	return seed, nil
+}
@@ -0,0 +1,92 @@
+package mock
+
+import (
+	"fmt"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+)
+
+// Store holds the transactions of a Ledger. The Ledger takes care of
+// sequencing, hashing and waking waiting readers, so a Store only has to keep
+// the sequenced transactions it's given and hand them back. Calls are
+// serialized by the Ledger; implementations don't need to be safe for
+// concurrent use.
+type Store interface {
+	// Seed returns the network seed of the stored ledger.
+	Seed() []byte
+
+	// Append stores a batch of sequenced transactions. The first of them has
+	// index LastIndex() + 1 and the rest follow without gaps. The batch is
+	// stored in full or, if an error is returned, not at all.
+	Append(txs []*api.SequencedTransaction) error
+
+	// Read returns up to count transactions, starting at index. Fewer are
+	// returned if the end of the store is reached. index must be between 1
+	// and LastIndex() + 1.
+	Read(index int64, count int) ([]*api.SequencedTransaction, error)
+
+	// LastIndex returns the index of the last stored transaction, or 0 if the
+	// store is empty.
+	LastIndex() int64
+
+	// StateHash returns the state hash of the last stored transaction, or nil
+	// if the store is empty.
+	StateHash() []byte
+
+	// Reset discards all stored transactions and replaces the network seed,
+	// leaving an empty store.
+	Reset(seed []byte) error
+}
+
+// MemoryStore is a Store holding all transactions in memory.
+type MemoryStore struct {
+	seed []byte
+	data []*api.SequencedTransaction
+}
+
+// NewMemoryStore creates an empty MemoryStore with the provided network seed.
+func NewMemoryStore(seed []byte) *MemoryStore {
+	return &MemoryStore{seed: seed}
+}
+
+func (s *MemoryStore) Seed() []byte {
+	return s.seed
+}
+
+func (s *MemoryStore) Append(txs []*api.SequencedTransaction) error {
+	if len(txs) > 0 && txs[0].Index != s.LastIndex()+1 {
+		return fmt.Errorf("Unexpected index (got %d, expected %d)", txs[0].Index, s.LastIndex()+1)
+	}
+	s.data = append(s.data, txs...)
+	return nil
+}
+
+// Read returns a slice of the stored transactions. Indexes counts from 1 and
+// length will be truncated to stay within bounds.
+func (s *MemoryStore) Read(index int64, count int) ([]*api.SequencedTransaction, error) {
+	i := int(index - 1)
+	if i < 0 || i > len(s.data) {
+		return nil, fmt.Errorf("Index %d out of range", index)
+	}
+	if count > len(s.data)-i {
+		count = len(s.data) - i
+	}
+	return s.data[i : i+count], nil
+}
+
+func (s *MemoryStore) LastIndex() int64 {
+	return int64(len(s.data))
+}
+
+func (s *MemoryStore) StateHash() []byte {
+	if len(s.data) == 0 {
+		return nil
+	}
+	return s.data[len(s.data)-1].StateHash
+}
+
+func (s *MemoryStore) Reset(seed []byte) error {
+	s.seed = seed
+	s.data = nil
+	return nil
+}
@@ -0,0 +1,166 @@
+package mock_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"golang.org/x/net/context"
+	"io/ioutil"
+	"os"
+
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/mock"
+
+	"github.com/nbio/st"
+	"testing"
+)
+
+// sequence turns data into a chain of sequenced transactions, continuing after
+// the provided index and state hash.
+func sequence(index int64, stateHash []byte, data ...string) []*api.SequencedTransaction {
+	var txs []*api.SequencedTransaction
+	for _, d := range data {
+		index++
+		hash := sha256.Sum256([]byte(d))
+		sh := sha256.Sum256(append(append([]byte{}, stateHash...), hash[:]...))
+		stateHash = sh[:]
+		txs = append(txs, &api.SequencedTransaction{
+			Index:     index,
+			Timestamp: index,
+			Data:      []byte(d),
+			Hash:      hash[:],
+			StateHash: stateHash,
+		})
+	}
+	return txs
+}
+
+func testStore(t *testing.T, s mock.Store) {
+	st.Expect(t, s.LastIndex(), int64(0))
+	st.Expect(t, len(s.StateHash()), 0)
+
+	batch1 := sequence(0, nil, "a", "b", "c")
+	st.Assert(t, s.Append(batch1), nil)
+	batch2 := sequence(3, s.StateHash(), "d", "e")
+	st.Assert(t, s.Append(batch2), nil)
+	st.Expect(t, s.LastIndex(), int64(5))
+	st.Expect(t, s.StateHash(), batch2[1].StateHash)
+
+	// Batches must follow on the last index.
+	st.Refute(t, s.Append(sequence(7, nil, "x")), nil)
+	st.Expect(t, s.LastIndex(), int64(5))
+
+	txs, err := s.Read(2, 3)
+	st.Assert(t, err, nil)
+	st.Assert(t, len(txs), 3)
+	for i, tx := range txs {
+		st.Expect(t, tx.Index, int64(2+i))
+	}
+	st.Expect(t, string(txs[2].Data), "d")
+
+	txs, err = s.Read(4, 100)
+	st.Assert(t, err, nil)
+	st.Expect(t, len(txs), 2)
+
+	txs, err = s.Read(6, 100)
+	st.Assert(t, err, nil)
+	st.Expect(t, len(txs), 0)
+}
+
+// testStoreReset checks that a store with transactions is emptied by Reset.
+func testStoreReset(t *testing.T, s mock.Store) {
+	st.Assert(t, s.Append(sequence(s.LastIndex(), s.StateHash(), "a")), nil)
+	st.Assert(t, s.Reset([]byte("new seed")), nil)
+	st.Expect(t, s.Seed(), []byte("new seed"))
+	st.Expect(t, s.LastIndex(), int64(0))
+	st.Expect(t, len(s.StateHash()), 0)
+	st.Assert(t, s.Append(sequence(0, nil, "b")), nil)
+	txs, err := s.Read(1, 100)
+	st.Assert(t, err, nil)
+	st.Assert(t, len(txs), 1)
+	st.Expect(t, string(txs[0].Data), "b")
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := mock.NewMemoryStore([]byte("seed"))
+	st.Expect(t, s.Seed(), []byte("seed"))
+	testStore(t, s)
+	testStoreReset(t, s)
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock-store")
+	st.Assert(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	s, err := mock.OpenFileStore(dir, 0)
+	st.Assert(t, err, nil)
+	defer s.Close()
+	st.Expect(t, len(s.Seed()), 32)
+	testStore(t, s)
+}
+
+func TestFileStoreReset(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock-store")
+	st.Assert(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	s, err := mock.OpenFileStore(dir, 1)
+	st.Assert(t, err, nil)
+	testStoreReset(t, s)
+	st.Assert(t, s.Close(), nil)
+
+	// The reset survives reopening the store.
+	s, err = mock.OpenFileStore(dir, 1)
+	st.Assert(t, err, nil)
+	defer s.Close()
+	st.Expect(t, s.Seed(), []byte("new seed"))
+	st.Expect(t, s.LastIndex(), int64(1))
+}
+
+func TestFileStoreSegments(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock-store")
+	st.Assert(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	// A tiny segment size makes every batch start a new segment.
+	s, err := mock.OpenFileStore(dir, 1)
+	st.Assert(t, err, nil)
+	testStore(t, s)
+	seed := s.Seed()
+	stateHash := s.StateHash()
+	st.Assert(t, s.Close(), nil)
+
+	s, err = mock.OpenFileStore(dir, 1)
+	st.Assert(t, err, nil)
+	defer s.Close()
+	st.Expect(t, s.Seed(), seed)
+	st.Expect(t, s.LastIndex(), int64(5))
+	st.Expect(t, s.StateHash(), stateHash)
+	txs, err := s.Read(1, 5)
+	st.Assert(t, err, nil)
+	st.Expect(t, len(txs), 5)
+	st.Expect(t, string(txs[4].Data), "e")
+}
+
+// failingStore is a MemoryStore that refuses appends.
+type failingStore struct {
+	*mock.MemoryStore
+}
+
+func (s failingStore) Append(_ []*api.SequencedTransaction) error {
+	return errors.New("disk full")
+}
+
+func TestLedgerStoreFailure(t *testing.T) {
+	l := mock.NewLedger(mock.WithStore(failingStore{mock.NewMemoryStore([]byte("seed"))}))
+
+	status, _ := l.ServerStatus(context.Background(), nil)
+	st.Expect(t, status.NetworkSeed, []byte("seed"))
+	_, err := l.AppendTransactions(context.Background(), &api.AppendRequest{
+		Transactions: []*api.UnsequencedTransaction{{Data: []byte("a")}},
+	})
+	_, ok := err.(api.ServerError)
+	st.Assert(t, ok, true)
+	status, _ = l.ServerStatus(context.Background(), nil)
+	st.Expect(t, status.LastIndex, int64(0))
+}
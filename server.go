@@ -1,16 +1,138 @@
 package main
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"flag"
+	"fmt"
 	"github.com/Sirupsen/logrus"
+	"golang.org/x/crypto/ed25519"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/symbiont-io/assembly-sdk/api"
+	"github.com/symbiont-io/assembly-sdk/api/grpc"
 	"github.com/symbiont-io/assembly-sdk/api/rest"
 	"github.com/symbiont-io/assembly-sdk/mock"
+	"github.com/symbiont-io/assembly-sdk/mock/faults"
+	"github.com/symbiont-io/assembly-sdk/mock/replicated"
 )
 
 var listen = flag.String("listen", "localhost:4000", "address to listen on")
+var grpcListen = flag.String("grpc-listen", "", "address to serve the gRPC API on (disabled if not set)")
+var dataDir = flag.String("data-dir", "", "directory to persist transactions in (in-memory if not set)")
+var seed = flag.String("seed", "", "hex-encoded network seed of a new ledger (random if not set)")
+var dedup = flag.String("dedup", "off", "reject duplicate transactions: off, full (whole history) or the number of recent transactions to check")
+var dedupReturnIndex = flag.Bool("dedup-return-index", false, "return the index of the original transaction for duplicates instead of rejecting them")
+var signedTypes = flag.String("signed-types", "", "comma-separated type filters of transactions that must be signed by their author to be appended (eg. \"orders/*\")")
+var admin = flag.Bool("admin", false, "enable the administrative routes under /admin, eg. for importing a history")
+var adminTokenFile = flag.String("admin-token-file", "", "file holding the token clients must send to use the administrative routes, generated if missing; enables them along with resetting the ledger")
+var faultsEnabled = flag.Bool("faults", false, "wrap the ledger in a fault-injection layer, configured with the --fault-* flags and, with --admin, at runtime on /admin/faults")
+var faultLatency = flag.String("fault-latency", "", "distribution of delays added to requests, eg. \"uniform:10ms,50ms\" (see api.ParseLatency)")
+var faultServerErrorRate = flag.Float64("fault-server-error-rate", 0, "rate of requests failed with a server error")
+var faultNotFoundRate = flag.Float64("fault-not-found-rate", 0, "rate of reads and lookups failed with a not found error")
+var faultAsyncDropRate = flag.Float64("fault-async-drop-rate", 0, "rate of asynchronous appends dropped")
+var faultNotReadyEvery = flag.Duration("fault-not-ready-every", 0, "period after which the ledger reports itself as not ready")
+var faultNotReadyFor = flag.Duration("fault-not-ready-for", 0, "time the ledger reports itself as not ready every period")
+var raftID = flag.String("raft-id", "", "ID of this node among --raft-peers, replicating the ledger with them over Raft (see mock/replicated)")
+var raftPeers = flag.String("raft-peers", "", "comma-separated nodes of the replicated ledger, including this one, each as <id>@<raft address>@<url> (eg. \"a@localhost:7000@http://localhost:4000\")")
+var signingKey = flag.String("signing-key", "", "file holding the hex-encoded Ed25519 private key to sign statuses and receipts with, generated if missing (unsigned if not set)")
+
+// dedupOptions returns the mock ledger options for the dedup flags.
+func dedupOptions() ([]mock.Option, error) {
+	window := int64(mock.DedupOff)
+	switch *dedup {
+	case "off":
+	case "full":
+		window = mock.DedupFullHistory
+	default:
+		n, err := strconv.ParseInt(*dedup, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("Invalid dedup mode %q", *dedup)
+		}
+		window = n
+	}
+	opts := []mock.Option{mock.WithDedup(window)}
+	if *dedupReturnIndex {
+		opts = append(opts, mock.WithDedupReturnIndex())
+	}
+	return opts, nil
+}
+
+// faultConfig returns the faults to inject set by the fault flags, and whether
+// the ledger should be wrapped in a fault-injection layer.
+func faultConfig() (api.FaultConfig, bool, error) {
+	c := api.FaultConfig{
+		ServerErrorRate: *faultServerErrorRate,
+		NotFoundRate:    *faultNotFoundRate,
+		AsyncDropRate:   *faultAsyncDropRate,
+		NotReadyEvery:   *faultNotReadyEvery,
+		NotReadyFor:     *faultNotReadyFor,
+	}
+	if *faultLatency != "" {
+		l, err := api.ParseLatency(*faultLatency)
+		if err != nil {
+			return c, false, err
+		}
+		c.Latency = l
+	}
+	return c, *faultsEnabled || c != api.FaultConfig{}, nil
+}
+
+// loadSigningKey reads the private key in the signing key file, generating a
+// new key and writing it to the file if it doesn't exist.
+func loadSigningKey(path string) (ed25519.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to generate signing key: %v", err)
+		}
+		err = ioutil.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0600)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to write signing key: %v", err)
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read signing key: %v", err)
+	}
+	key, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("Invalid signing key in %q", path)
+	}
+	return ed25519.PrivateKey(key), nil
+}
+
+// loadAdminToken reads the admin token in the file, generating a new random
+// token and writing it to the file if it doesn't exist.
+func loadAdminToken(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return "", fmt.Errorf("Failed to generate admin token: %v", err)
+		}
+		token := hex.EncodeToString(b)
+		if err := ioutil.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+			return "", fmt.Errorf("Failed to write admin token: %v", err)
+		}
+		return token, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Failed to read admin token: %v", err)
+	}
+	token := string(bytes.TrimSpace(data))
+	if token == "" {
+		return "", fmt.Errorf("Empty admin token in %q", path)
+	}
+	return token, nil
+}
 
 func newLogger() *logrus.Logger {
 	logger := logrus.New()
@@ -28,7 +150,99 @@ func main() {
 	flag.Parse()
 
 	logger := newLogger()
-	s := rest.NewServer(mock.NewLedger(), rest.WithLogger(logger))
+	opts, err := dedupOptions()
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	var seedBytes []byte
+	if *seed != "" {
+		seedBytes, err = hex.DecodeString(*seed)
+		if err != nil || len(seedBytes) == 0 {
+			logger.Fatalf("Invalid seed %q", *seed)
+		}
+		opts = append(opts, mock.WithSeed(seedBytes))
+	}
+	if *signingKey != "" {
+		key, err := loadSigningKey(*signingKey)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		opts = append(opts, mock.WithSigningKey(key))
+		logger.Printf("Signing with public key %x", []byte(key.Public().(ed25519.PublicKey)))
+	}
+	var server api.LedgerServer
+	if *raftID != "" {
+		if *dataDir != "" {
+			logger.Fatalf("Replicated ledgers are kept in memory, --data-dir isn't supported")
+		}
+		peers, err := replicated.ParsePeers(*raftPeers)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		ropts := []replicated.Option{
+			replicated.WithLedgerOptions(opts...),
+			replicated.WithLogOutput(os.Stdout),
+		}
+		if seedBytes != nil {
+			ropts = append(ropts, replicated.WithSeed(seedBytes))
+		}
+		node, err := replicated.NewNode(*raftID, peers, ropts...)
+		if err != nil {
+			logger.Fatalf("Failed to start node %q: %v", *raftID, err)
+		}
+		server = node
+		logger.Printf("Replicating the ledger over Raft as node %q", *raftID)
+	} else if *dataDir != "" {
+		ledger, err := mock.OpenLedger(*dataDir, opts...)
+		if err != nil {
+			logger.Fatalf("Failed to open ledger in %q: %v", *dataDir, err)
+		}
+		server = ledger
+		logger.Println("Storing transactions in", *dataDir)
+	} else {
+		server = mock.NewLedger(opts...)
+	}
+	fc, wrap, err := faultConfig()
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	if wrap {
+		f := faults.NewLedger(server)
+		if err := f.SetConfig(fc); err != nil {
+			logger.Fatalf("Invalid faults: %v", err)
+		}
+		server = f
+		logger.Printf("Injecting faults: %+v", fc)
+	}
+	restOpts := []rest.Option{rest.WithLogger(logger)}
+	if *signedTypes != "" {
+		restOpts = append(restOpts, rest.WithSignedTypes(strings.Split(*signedTypes, ",")...))
+		logger.Println("Requiring signatures on types", *signedTypes)
+	}
+	if *adminTokenFile != "" {
+		token, err := loadAdminToken(*adminTokenFile)
+		if err != nil {
+			logger.Fatalf("%v", err)
+		}
+		restOpts = append(restOpts, rest.WithAdminToken(token))
+		logger.Println("Serving administrative routes with the token in", *adminTokenFile)
+	} else if *admin {
+		restOpts = append(restOpts, rest.WithAdmin())
+		logger.Println("Serving administrative routes")
+	}
+	s := rest.NewServer(server, restOpts...)
+
+	if *grpcListen != "" {
+		lis, err := net.Listen("tcp", *grpcListen)
+		if err != nil {
+			logger.Fatalf("Failed to listen on %q: %v", *grpcListen, err)
+		}
+		g := grpc.NewServer(server, grpc.WithLogger(logger))
+		logger.Println("Serving gRPC on", *grpcListen)
+		go func() {
+			logger.Println(g.Serve(lis))
+		}()
+	}
 
 	logger.Println("Listening on", *listen)
 	logger.Println(http.ListenAndServe(*listen, s.Router()))
@@ -114,7 +114,7 @@ func TestAppendRead(t *testing.T) {
 	ctx := context.Background()
 	status, err := l.ServerStatus(ctx, nil)
 	st.Assert(t, err, nil)
-	appendRes, err := c.AppendTransactions(ctx, &api.AppendRequest{status.NetworkSeed, txs})
+	appendRes, err := c.AppendTransactions(ctx, &api.AppendRequest{NetworkSeed: status.NetworkSeed, Transactions: txs})
 	st.Assert(t, err, nil)
 	st.Expect(t, appendRes.LastIndex, int64(n))
 
@@ -150,7 +150,7 @@ func TestAppendReadOffset(t *testing.T) {
 	ctx := context.Background()
 	status, err := l.ServerStatus(ctx, nil)
 	st.Assert(t, err, nil)
-	appendRes, err := c.AppendTransactions(ctx, &api.AppendRequest{status.NetworkSeed, txs})
+	appendRes, err := c.AppendTransactions(ctx, &api.AppendRequest{NetworkSeed: status.NetworkSeed, Transactions: txs})
 	st.Assert(t, err, nil)
 	st.Expect(t, appendRes.LastIndex, int64(n))
 
@@ -178,7 +178,7 @@ func TestBadNetworkSeed(t *testing.T) {
 	ctx := context.Background()
 	status, err := l.ServerStatus(ctx, nil)
 	st.Assert(t, err, nil)
-	_, err = c.AppendTransactions(ctx, &api.AppendRequest{[]byte("bad seed"), txs})
+	_, err = c.AppendTransactions(ctx, &api.AppendRequest{NetworkSeed: []byte("bad seed"), Transactions: txs})
 	st.Reject(t, err, nil)
 	e, ok := err.(api.NetworkSeedMismatchError)
 	st.Assert(t, ok, true)